@@ -7,12 +7,12 @@ import (
 	"github.com/containous/mux"
 	"github.com/containous/traefik/pkg/config"
 	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/render"
 	"github.com/containous/traefik/pkg/safe"
 	"github.com/containous/traefik/pkg/types"
 	"github.com/containous/traefik/pkg/version"
 	assetfs "github.com/elazarl/go-bindata-assetfs"
 	thoasstats "github.com/thoas/stats"
-	"github.com/unrolled/render"
 )
 
 // ResourceIdentifier a resource identifier
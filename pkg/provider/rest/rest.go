@@ -10,8 +10,8 @@ import (
 	"github.com/containous/traefik/pkg/config"
 	"github.com/containous/traefik/pkg/log"
 	"github.com/containous/traefik/pkg/provider"
+	"github.com/containous/traefik/pkg/render"
 	"github.com/containous/traefik/pkg/safe"
-	"github.com/unrolled/render"
 )
 
 var _ provider.Provider = (*Provider)(nil)
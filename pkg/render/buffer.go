@@ -8,7 +8,9 @@ var bufPool *BufferPool
 // BufferPool implements a pool of bytes.Buffers in the form of a bounded channel.
 // Pulled from the github.com/oxtoacart/bpool package (Apache licensed).
 type BufferPool struct {
-	c chan *bytes.Buffer
+	c       chan *bytes.Buffer
+	alloc   int
+	maxSize int
 }
 
 // NewBufferPool creates a new BufferPool bounded to the given size.
@@ -18,6 +20,24 @@ func NewBufferPool(size int) (bp *BufferPool) {
 	}
 }
 
+// NewSizedBufferPool creates a new BufferPool bounded to the given size.
+// New buffers are allocated with the given capacity, and buffers which grew
+// beyond maxSize are discarded on Put instead of being kept in the pool.
+// A zero maxSize keeps buffers of any size.
+func NewSizedBufferPool(size, alloc, maxSize int) (bp *BufferPool) {
+	return &BufferPool{
+		c:       make(chan *bytes.Buffer, size),
+		alloc:   alloc,
+		maxSize: maxSize,
+	}
+}
+
+// SetBufferPool replaces the pool used to render into. It is meant to be
+// called during initialization, before any rendering happens.
+func SetBufferPool(bp *BufferPool) {
+	bufPool = bp
+}
+
 // Get gets a Buffer from the BufferPool, or creates a new one if none are
 // available in the pool.
 func (bp *BufferPool) Get() (b *bytes.Buffer) {
@@ -26,13 +46,18 @@ func (bp *BufferPool) Get() (b *bytes.Buffer) {
 	// reuse existing buffer
 	default:
 		// create new buffer
-		b = bytes.NewBuffer([]byte{})
+		b = bytes.NewBuffer(make([]byte, 0, bp.alloc))
 	}
 	return
 }
 
 // Put returns the given Buffer to the BufferPool.
 func (bp *BufferPool) Put(b *bytes.Buffer) {
+	if bp.maxSize > 0 && b.Cap() > bp.maxSize {
+		// Don't pin oversized buffers in the pool.
+		return
+	}
+
 	b.Reset()
 	select {
 	case bp.c <- b:
@@ -0,0 +1,171 @@
+package render
+
+import (
+	"container/list"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cached is a wrapper engine serving the output of another engine from a cache,
+// e.g. for expensive template renders or large JSON changing infrequently.
+//
+// Key returns the cache key of the value being rendered, an empty key bypassing
+// the cache. Renders are cached along with their headers, failed renders never
+// are. Values are shared between copies of the engine through Cache, see
+// NewRenderCache. Key and Cache are required.
+//
+// Only responses any client can be served are cached: 200 responses which don't
+// vary by request nor carry a Content-Encoding or a Content-Security-Policy
+// nonce. Wrapping engines such as ETag with IfNoneMatch, Gzip, Negotiate or
+// HTML with Nonce is therefore pointless, their responses are rendered anew
+// every time.
+type Cached struct {
+	Engine Engine
+	Key    func(v interface{}) string
+	Cache  *RenderCache
+}
+
+// Render a response, from the cache if possible.
+func (c Cached) Render(w io.Writer, v interface{}) error {
+	if c.Key == nil || c.Cache == nil {
+		return errors.New("render: Cached needs a Key and a Cache")
+	}
+
+	key := c.Key(v)
+	if key == "" {
+		return c.Engine.Render(w, v)
+	}
+
+	entry, ok := c.Cache.get(key)
+	if !ok {
+		rec := newBufferedResponseWriter()
+		if err := c.Engine.Render(rec, v); err != nil {
+			return err
+		}
+		entry = &cacheEntry{key: key, header: rec.header, status: rec.status, body: rec.body.Bytes()}
+		if cacheable(entry) {
+			c.Cache.add(entry)
+		}
+	}
+
+	if hw, ok := w.(http.ResponseWriter); ok {
+		for k, v := range entry.header {
+			hw.Header()[k] = append([]string(nil), v...)
+		}
+		if entry.status != 0 {
+			hw.WriteHeader(entry.status)
+		}
+	}
+	_, err := w.Write(entry.body)
+	return err
+}
+
+// cacheable reports whether the rendered response can be served to any client.
+func cacheable(entry *cacheEntry) bool {
+	if entry.status != 0 && entry.status != http.StatusOK {
+		return false
+	}
+	if entry.header.Get(Vary) != "" || entry.header.Get(ContentEncoding) != "" {
+		return false
+	}
+	for _, policy := range entry.header[ContentSecurityPolicy] {
+		if strings.Contains(policy, "'nonce-") {
+			return false
+		}
+	}
+	return true
+}
+
+// RenderCache is a least recently used cache of rendered responses, safe for
+// concurrent use.
+type RenderCache struct {
+	ttl        time.Duration
+	maxEntries int
+	maxBytes   int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	size    int
+}
+
+// cacheEntry is a rendered response held by a RenderCache.
+type cacheEntry struct {
+	key     string
+	header  http.Header
+	status  int
+	body    []byte
+	expires time.Time
+}
+
+// NewRenderCache returns a cache for the Cached engine. Entries expire after ttl
+// and the least recently used ones are evicted when the cache holds more than
+// maxEntries entries or maxBytes bytes of bodies. A zero ttl or bound means no limit.
+func NewRenderCache(ttl time.Duration, maxEntries, maxBytes int) *RenderCache {
+	return &RenderCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// get returns the entry cached under key unless it expired.
+func (c *RenderCache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if c.ttl > 0 && time.Now().After(entry.expires) {
+		c.remove(el)
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return entry, true
+}
+
+// add caches the entry, evicting the least recently used entries over the bounds.
+// Entries bigger than maxBytes on their own aren't cached.
+func (c *RenderCache) add(entry *cacheEntry) {
+	if c.maxBytes > 0 && len(entry.body) > c.maxBytes {
+		return
+	}
+	if c.ttl > 0 {
+		entry.expires = time.Now().Add(c.ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[entry.key]; ok {
+		c.remove(el)
+	}
+	c.entries[entry.key] = c.lru.PushFront(entry)
+	c.size += len(entry.body)
+
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries || c.maxBytes > 0 && c.size > c.maxBytes {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *RenderCache) remove(el *list.Element) {
+	entry := c.lru.Remove(el).(*cacheEntry)
+	delete(c.entries, entry.key)
+	c.size -= len(entry.body)
+}
+
+// Len returns the number of cached entries, expired ones included until looked up or evicted.
+func (c *RenderCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
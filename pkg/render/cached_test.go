@@ -0,0 +1,84 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCached_Render(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		engine   Engine
+		expected int
+	}{
+		{
+			desc:     "ok response",
+			engine:   Text{},
+			expected: 1,
+		},
+		{
+			desc:     "not found response",
+			engine:   Text{Head: Head{Status: http.StatusNotFound}},
+			expected: 0,
+		},
+		{
+			desc:     "created response",
+			engine:   Text{Head: Head{Status: http.StatusCreated}},
+			expected: 0,
+		},
+		{
+			desc:     "response varying by request",
+			engine:   Text{Head: Head{Vary: []string{"Accept"}}},
+			expected: 0,
+		},
+		{
+			desc:     "gzip response",
+			engine:   Gzip{Engine: Text{}, AcceptEncoding: "gzip"},
+			expected: 0,
+		},
+		{
+			desc:     "response with a nonce",
+			engine:   Text{Head: Head{Headers: http.Header{ContentSecurityPolicy: {"script-src 'nonce-abc'"}}}},
+			expected: 0,
+		},
+		{
+			desc:     "response with a policy",
+			engine:   Text{Head: Head{Headers: http.Header{ContentSecurityPolicy: {"script-src 'self'"}}}},
+			expected: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cache := NewRenderCache(0, 0, 0)
+			engine := Cached{
+				Engine: test.engine,
+				Key:    func(v interface{}) string { return v.(string) },
+				Cache:  cache,
+			}
+
+			for i := 0; i < 2; i++ {
+				recorder := httptest.NewRecorder()
+				err := engine.Render(recorder, "hello")
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expected, cache.Len())
+		})
+	}
+}
+
+func TestCached_Render_missingFields(t *testing.T) {
+	err := Cached{Engine: Text{}}.Render(httptest.NewRecorder(), "hello")
+	assert.Error(t, err)
+
+	err = Cached{Engine: Text{}, Cache: NewRenderCache(0, 0, 0)}.Render(httptest.NewRecorder(), "hello")
+	assert.Error(t, err)
+}
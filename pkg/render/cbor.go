@@ -0,0 +1,266 @@
+package render
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CBOR built-in renderer for RFC 8949 Concise Binary Object Representation.
+//
+// Struct fields are encoded as map entries named after the `cbor` struct tag,
+// falling back to the field name, and honor the "-" and omitempty options.
+// Map entries are sorted by their encoded key and time.Time values are encoded
+// as RFC 3339 strings (tag 0).
+type CBOR struct {
+	Head
+	// Streaming encodes straight to the writer once the head is written.
+	// Like StreamingJSON, an encoding error can't change the status anymore.
+	Streaming bool
+}
+
+// Render a CBOR response.
+func (c CBOR) Render(w io.Writer, v interface{}) error {
+	v = c.Head.errorPayload(v)
+
+	if c.Streaming {
+		if hw, ok := w.(http.ResponseWriter); ok {
+			c.writeHead(hw)
+		}
+		enc := &cborEncoder{w: &errWriter{w: w}}
+		return enc.encode(v)
+	}
+
+	// Retrieve a buffer from the pool to encode into.
+	buf := bufPool.Get()
+	defer bufPool.Put(buf)
+
+	enc := &cborEncoder{w: &errWriter{w: buf}}
+	if err := enc.encode(v); err != nil {
+		return err
+	}
+
+	// CBOR encoded fine, write out the result.
+	if hw, ok := w.(http.ResponseWriter); ok {
+		hw.Header().Set(ContentLength, strconv.Itoa(buf.Len()))
+		c.writeHead(hw)
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func (c CBOR) writeHead(hw http.ResponseWriter) {
+	if c.Head.ContentType == "" {
+		c.Head.ContentType = ContentCBOR
+	}
+	c.Head.Write(hw)
+}
+
+// CBOR major types.
+const (
+	cborUnsigned byte = iota
+	cborNegative
+	cborBytes
+	cborText
+	cborArray
+	cborMap
+	cborTag
+	cborSimple
+)
+
+// cborEncoder encodes values to w, keeping the first error.
+type cborEncoder struct {
+	w   *errWriter
+	err error
+}
+
+func (e *cborEncoder) encode(v interface{}) error {
+	e.value(reflect.ValueOf(v))
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.err
+}
+
+// head writes the initial byte of a data item with its argument n.
+func (e *cborEncoder) head(major byte, n uint64) {
+	var b [9]byte
+	b[0] = major << 5
+	switch {
+	case n < 24:
+		b[0] |= byte(n)
+		e.w.Write(b[:1])
+	case n <= math.MaxUint8:
+		b[0] |= 24
+		b[1] = byte(n)
+		e.w.Write(b[:2])
+	case n <= math.MaxUint16:
+		b[0] |= 25
+		binary.BigEndian.PutUint16(b[1:], uint16(n))
+		e.w.Write(b[:3])
+	case n <= math.MaxUint32:
+		b[0] |= 26
+		binary.BigEndian.PutUint32(b[1:], uint32(n))
+		e.w.Write(b[:5])
+	default:
+		b[0] |= 27
+		binary.BigEndian.PutUint64(b[1:], n)
+		e.w.Write(b[:9])
+	}
+}
+
+func (e *cborEncoder) value(rv reflect.Value) {
+	if e.err != nil {
+		return
+	}
+	if !rv.IsValid() {
+		e.w.Write([]byte{0xf6})
+		return
+	}
+	if rv.Type() == timeType {
+		e.head(cborTag, 0)
+		e.text(rv.Interface().(time.Time).Format(time.RFC3339Nano))
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			e.w.Write([]byte{0xf6})
+			return
+		}
+		e.value(rv.Elem())
+	case reflect.Bool:
+		if rv.Bool() {
+			e.w.Write([]byte{0xf5})
+		} else {
+			e.w.Write([]byte{0xf4})
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i := rv.Int(); i < 0 {
+			e.head(cborNegative, uint64(^i))
+		} else {
+			e.head(cborUnsigned, uint64(i))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.head(cborUnsigned, rv.Uint())
+	case reflect.Float32:
+		var b [5]byte
+		b[0] = 0xfa
+		binary.BigEndian.PutUint32(b[1:], math.Float32bits(float32(rv.Float())))
+		e.w.Write(b[:])
+	case reflect.Float64:
+		var b [9]byte
+		b[0] = 0xfb
+		binary.BigEndian.PutUint64(b[1:], math.Float64bits(rv.Float()))
+		e.w.Write(b[:])
+	case reflect.String:
+		e.text(rv.String())
+	case reflect.Slice:
+		if rv.IsNil() {
+			e.w.Write([]byte{0xf6})
+			return
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			e.head(cborBytes, uint64(rv.Len()))
+			e.w.Write(rv.Bytes())
+			return
+		}
+		fallthrough
+	case reflect.Array:
+		e.head(cborArray, uint64(rv.Len()))
+		for i := 0; i < rv.Len(); i++ {
+			e.value(rv.Index(i))
+		}
+	case reflect.Map:
+		if rv.IsNil() {
+			e.w.Write([]byte{0xf6})
+			return
+		}
+		e.mapValue(rv)
+	case reflect.Struct:
+		e.structValue(rv)
+	default:
+		e.err = fmt.Errorf("render: CBOR cannot encode %s", rv.Type())
+	}
+}
+
+func (e *cborEncoder) text(s string) {
+	e.head(cborText, uint64(len(s)))
+	e.w.Write([]byte(s))
+}
+
+// mapValue encodes a map with its entries sorted by their encoded key.
+func (e *cborEncoder) mapValue(rv reflect.Value) {
+	type entry struct {
+		key   []byte
+		value reflect.Value
+	}
+
+	entries := make([]entry, 0, rv.Len())
+	for _, k := range rv.MapKeys() {
+		var key bytes.Buffer
+		ke := &cborEncoder{w: &errWriter{w: &key}}
+		if err := ke.encode(k.Interface()); err != nil {
+			e.err = err
+			return
+		}
+		entries = append(entries, entry{key: key.Bytes(), value: rv.MapIndex(k)})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+
+	e.head(cborMap, uint64(len(entries)))
+	for _, en := range entries {
+		e.w.Write(en.key)
+		e.value(en.value)
+	}
+}
+
+// structValue encodes a struct as a map of its exported fields, in field order.
+func (e *cborEncoder) structValue(rv reflect.Value) {
+	var names []string
+	var values []reflect.Value
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name, opts := f.Name, ""
+		if tag := f.Tag.Get("cbor"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if idx := strings.Index(tag, ","); idx != -1 {
+				tag, opts = tag[:idx], tag[idx+1:]
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+
+		fv := rv.Field(i)
+		if hasOption(opts, "omitempty") && isEmptyValue(fv) {
+			continue
+		}
+		names = append(names, name)
+		values = append(values, fv)
+	}
+
+	e.head(cborMap, uint64(len(names)))
+	for i, name := range names {
+		e.text(name)
+		e.value(values[i])
+	}
+}
@@ -0,0 +1,96 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Transcoder converts UTF-8 text to another charset, e.g. by wrapping an
+// encoding of golang.org/x/text.
+type Transcoder func(src []byte) ([]byte, error)
+
+// TranscodeLatin1 is a Transcoder to ISO-8859-1, failing on characters it
+// can't represent.
+func TranscodeLatin1(src []byte) ([]byte, error) {
+	dst := make([]byte, 0, len(src))
+	for i := 0; i < len(src); {
+		r, size := utf8.DecodeRune(src[i:])
+		if r > 0xff || (r == utf8.RuneError && size == 1) {
+			return nil, fmt.Errorf("render: cannot transcode %q to ISO-8859-1", src[i:i+size])
+		}
+		dst = append(dst, byte(r))
+		i += size
+	}
+	return dst, nil
+}
+
+// transcode converts result to the charset the acceptCharset header value
+// prefers among the non-nil transcoders, which are keyed by charset name, when it
+// doesn't accept UTF-8. The content type is updated accordingly and Vary:
+// Accept-Charset added either way. Without an acceptable transcoder, the
+// result is left in UTF-8.
+func (h *Head) transcode(result []byte, acceptCharset string, transcoders map[string]Transcoder) ([]byte, error) {
+	// Copy Vary so the engine's slice is never appended to.
+	h.Vary = append(append([]string(nil), h.Vary...), "Accept-Charset")
+
+	ranges := parseAccept(acceptCharset)
+	if acceptQuality(ranges, "utf-8") > 0 {
+		return result, nil
+	}
+
+	names := make([]string, 0, len(transcoders))
+	for name, transcoder := range transcoders {
+		if transcoder != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	best, bestQ := "", 0.0
+	for _, name := range names {
+		if q := acceptQuality(ranges, name); q > bestQ {
+			best, bestQ = name, q
+		}
+	}
+	if best == "" {
+		return result, nil
+	}
+
+	result, err := transcoders[best](result)
+	if err != nil {
+		return nil, err
+	}
+	h.ContentType = withCharset(h.ContentType, best)
+	return result, nil
+}
+
+// acceptQuality returns the quality of a charset or a content coding in the
+// parsed Accept-Charset or Accept-Encoding ranges, an exact match taking
+// precedence over "*".
+func acceptQuality(ranges []acceptRange, value string) float64 {
+	value = strings.ToLower(value)
+	q := 0.0
+	for _, ar := range ranges {
+		switch ar.mediaType {
+		case value:
+			return ar.q
+		case "*":
+			q = ar.q
+		}
+	}
+	return q
+}
+
+// withCharset replaces the charset parameter of contentType.
+func withCharset(contentType, charset string) string {
+	params := strings.Split(contentType, ";")
+	kept := params[:1]
+	for _, param := range params[1:] {
+		if !strings.HasPrefix(strings.ToLower(strings.TrimSpace(param)), "charset=") {
+			kept = append(kept, param)
+		}
+	}
+	return strings.Join(kept, ";") + "; charset=" + charset
+}
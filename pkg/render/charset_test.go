@@ -0,0 +1,197 @@
+package render
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranscodeLatin1(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		src      string
+		expected string
+	}{
+		{
+			desc:     "ASCII",
+			src:      "hello",
+			expected: "hello",
+		},
+		{
+			desc:     "Latin-1 characters",
+			src:      "café ÿ",
+			expected: "caf\xe9 \xff",
+		},
+		{
+			desc:     "empty",
+			src:      "",
+			expected: "",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := TranscodeLatin1([]byte(test.src))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, string(result))
+		})
+	}
+}
+
+func TestTranscodeLatin1_error(t *testing.T) {
+	testCases := []struct {
+		desc string
+		src  string
+	}{
+		{
+			desc: "character out of range",
+			src:  "5 €",
+		},
+		{
+			desc: "invalid UTF-8",
+			src:  "a\xffb",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := TranscodeLatin1([]byte(test.src))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestText_Render_transcode(t *testing.T) {
+	transcoders := map[string]Transcoder{"iso-8859-1": TranscodeLatin1, "broken": nil}
+
+	testCases := []struct {
+		desc                string
+		acceptCharset       string
+		transcoders         map[string]Transcoder
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			desc:                "no Accept-Charset",
+			transcoders:         transcoders,
+			expectedContentType: "text/plain; charset=UTF-8",
+			expectedBody:        "café",
+		},
+		{
+			desc:                "UTF-8 accepted",
+			acceptCharset:       "iso-8859-1, utf-8;q=0.5",
+			transcoders:         transcoders,
+			expectedContentType: "text/plain; charset=UTF-8",
+			expectedBody:        "café",
+		},
+		{
+			desc:                "Latin-1 only",
+			acceptCharset:       "ISO-8859-1",
+			transcoders:         transcoders,
+			expectedContentType: "text/plain; charset=iso-8859-1",
+			expectedBody:        "caf\xe9",
+		},
+		{
+			desc:                "nil transcoder",
+			acceptCharset:       "broken",
+			transcoders:         transcoders,
+			expectedContentType: "text/plain; charset=UTF-8",
+			expectedBody:        "café",
+		},
+		{
+			desc:                "no acceptable transcoder",
+			acceptCharset:       "shift_jis",
+			transcoders:         transcoders,
+			expectedContentType: "text/plain; charset=UTF-8",
+			expectedBody:        "café",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			engine := Text{
+				Head:          Head{Status: http.StatusOK, ContentType: "text/plain; charset=UTF-8"},
+				AcceptCharset: test.acceptCharset,
+				Transcoders:   test.transcoders,
+			}
+			recorder := httptest.NewRecorder()
+			err := engine.Render(recorder, "café")
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedContentType, recorder.Header().Get(ContentType))
+			assert.Equal(t, "Accept-Charset", recorder.Header().Get(Vary))
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestHTML_Render_transcode(t *testing.T) {
+	tmpl, err := template.New("page").Parse(`<p>{{ . }}</p>`)
+	require.NoError(t, err)
+
+	h := HTML{
+		Head:          Head{Status: http.StatusOK, ContentType: ContentHTML + "; charset=UTF-8"},
+		Templates:     tmpl,
+		Name:          "page",
+		AcceptCharset: "iso-8859-1",
+		Transcoders:   map[string]Transcoder{"iso-8859-1": TranscodeLatin1},
+	}
+	recorder := httptest.NewRecorder()
+	err = h.Render(recorder, "café")
+	require.NoError(t, err)
+
+	assert.Equal(t, ContentHTML+"; charset=iso-8859-1", recorder.Header().Get(ContentType))
+	assert.Equal(t, "<p>caf\xe9</p>", recorder.Body.String())
+}
+
+func TestText_Render_transcodeError(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		transcoder Transcoder
+		value      string
+	}{
+		{
+			desc:       "character out of range",
+			transcoder: TranscodeLatin1,
+			value:      "5 €",
+		},
+		{
+			desc:       "failing transcoder",
+			transcoder: func([]byte) ([]byte, error) { return nil, errors.New("failed") },
+			value:      "a",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			engine := Text{
+				Head:          Head{Status: http.StatusOK},
+				AcceptCharset: "latin1",
+				Transcoders:   map[string]Transcoder{"latin1": test.transcoder},
+			}
+			recorder := httptest.NewRecorder()
+			err := engine.Render(recorder, test.value)
+			require.Error(t, err)
+
+			assert.Empty(t, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package render
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// CORS holds the Access-Control-* headers written along with a response,
+// covering simple cross-origin setups without a separate middleware.
+type CORS struct {
+	AllowOrigin      string
+	AllowMethods     []string
+	AllowHeaders     []string
+	ExposeHeaders    []string
+	AllowCredentials bool
+	// MaxAge is how long, in seconds, preflight results can be cached.
+	MaxAge int
+}
+
+// write sets the non-empty CORS headers.
+func (c *CORS) write(header http.Header) {
+	if c.AllowOrigin != "" {
+		header.Set("Access-Control-Allow-Origin", c.AllowOrigin)
+		if c.AllowOrigin != "*" {
+			addVary(header, "Origin")
+		}
+	}
+	if len(c.AllowMethods) > 0 {
+		header.Set("Access-Control-Allow-Methods", strings.Join(c.AllowMethods, ", "))
+	}
+	if len(c.AllowHeaders) > 0 {
+		header.Set("Access-Control-Allow-Headers", strings.Join(c.AllowHeaders, ", "))
+	}
+	if len(c.ExposeHeaders) > 0 {
+		header.Set("Access-Control-Expose-Headers", strings.Join(c.ExposeHeaders, ", "))
+	}
+	if c.AllowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if c.MaxAge > 0 {
+		header.Set("Access-Control-Max-Age", strconv.Itoa(c.MaxAge))
+	}
+}
@@ -0,0 +1,73 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHead_Write_cors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		cors     *CORS
+		expected http.Header
+	}{
+		{
+			desc:     "nil",
+			expected: http.Header{},
+		},
+		{
+			desc:     "empty",
+			cors:     &CORS{},
+			expected: http.Header{},
+		},
+		{
+			desc: "any origin",
+			cors: &CORS{AllowOrigin: "*"},
+			expected: http.Header{
+				"Access-Control-Allow-Origin": {"*"},
+			},
+		},
+		{
+			desc: "all headers",
+			cors: &CORS{
+				AllowOrigin:      "https://example.com",
+				AllowMethods:     []string{http.MethodGet, http.MethodPost},
+				AllowHeaders:     []string{"Authorization", "Content-Type"},
+				ExposeHeaders:    []string{"X-Request-Id"},
+				AllowCredentials: true,
+				MaxAge:           600,
+			},
+			expected: http.Header{
+				"Access-Control-Allow-Origin":      {"https://example.com"},
+				"Access-Control-Allow-Methods":     {"GET, POST"},
+				"Access-Control-Allow-Headers":     {"Authorization, Content-Type"},
+				"Access-Control-Expose-Headers":    {"X-Request-Id"},
+				"Access-Control-Allow-Credentials": {"true"},
+				"Access-Control-Max-Age":           {"600"},
+				"Vary":                             {"Origin"},
+			},
+		},
+		{
+			desc:     "negative max age",
+			cors:     &CORS{MaxAge: -1},
+			expected: http.Header{},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			Head{Status: http.StatusOK, CORS: test.cors}.Write(recorder)
+
+			header := recorder.Header()
+			header.Del(ContentType)
+			assert.Equal(t, test.expected, header)
+		})
+	}
+}
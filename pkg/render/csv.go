@@ -0,0 +1,126 @@
+package render
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// CSV built-in renderer.
+type CSV struct {
+	Head
+	WriteHeader bool
+	Comma       rune
+}
+
+// Render a CSV response.
+func (c CSV) Render(w io.Writer, v interface{}) error {
+	records, err := csvRecords(v, c.WriteHeader)
+	if err != nil {
+		return err
+	}
+
+	// Retrieve a buffer from the pool to write to.
+	out := bufPool.Get()
+	defer bufPool.Put(out)
+
+	cw := csv.NewWriter(out)
+	if c.Comma != 0 {
+		cw.Comma = c.Comma
+	}
+	if err := cw.WriteAll(records); err != nil {
+		return err
+	}
+
+	// CSV encoded fine, write out the result.
+	if hw, ok := w.(http.ResponseWriter); ok {
+		c.Head.setDefaultContentType(ContentCSV)
+		c.Head.Write(hw)
+	}
+	_, err = out.WriteTo(w)
+	return err
+}
+
+// csvRecords converts either a [][]string or a slice of structs into CSV records.
+func csvRecords(v interface{}, header bool) ([][]string, error) {
+	if records, ok := v.([][]string); ok {
+		return records, nil
+	}
+
+	names, records, ok := structRecords(v, "csv")
+	if !ok {
+		return nil, fmt.Errorf("render: CSV expects [][]string or a slice of structs, got %T", v)
+	}
+	if header {
+		records = append([][]string{names}, records...)
+	}
+	return records, nil
+}
+
+// structRecords converts a slice or array of structs, or of pointers to structs,
+// into records of formatted field values along with the column names. It reports
+// false when v isn't such a slice.
+func structRecords(v interface{}, tagName string) ([]string, [][]string, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, nil, false
+	}
+
+	et := rv.Type().Elem()
+	if et.Kind() == reflect.Ptr {
+		et = et.Elem()
+	}
+	if et.Kind() != reflect.Struct {
+		return nil, nil, false
+	}
+
+	names, fields := structFields(et, tagName)
+
+	records := make([][]string, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		ev := rv.Index(i)
+		if ev.Kind() == reflect.Ptr {
+			if ev.IsNil() {
+				records = append(records, make([]string, len(fields)))
+				continue
+			}
+			ev = ev.Elem()
+		}
+
+		record := make([]string, len(fields))
+		for j, f := range fields {
+			record[j] = fmt.Sprint(ev.Field(f).Interface())
+		}
+		records = append(records, record)
+	}
+	return names, records, true
+}
+
+// structFields returns the column names and field indexes of the exported fields of t.
+// Column names are taken from the tagName struct tag, falling back to the field name.
+func structFields(t reflect.Type, tagName string) ([]string, []int) {
+	var names []string
+	var fields []int
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name := f.Name
+		if tag := f.Tag.Get(tagName); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if n := strings.Split(tag, ",")[0]; n != "" {
+				name = n
+			}
+		}
+		names = append(names, name)
+		fields = append(fields, i)
+	}
+	return names, fields
+}
@@ -0,0 +1,197 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestData_Render_lastModified(t *testing.T) {
+	modified := time.Date(2019, 1, 2, 3, 4, 5, 600, time.UTC)
+
+	testCases := []struct {
+		desc            string
+		lastModified    time.Time
+		ifModifiedSince string
+		expectedStatus  int
+		expectedHeader  string
+		expectedBody    string
+	}{
+		{
+			desc:           "no last modification",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "hello",
+		},
+		{
+			desc:           "no If-Modified-Since",
+			lastModified:   modified,
+			expectedStatus: http.StatusOK,
+			expectedHeader: "Wed, 02 Jan 2019 03:04:05 GMT",
+			expectedBody:   "hello",
+		},
+		{
+			desc:            "not modified since",
+			lastModified:    modified,
+			ifModifiedSince: "Wed, 02 Jan 2019 03:04:05 GMT",
+			expectedStatus:  http.StatusNotModified,
+			expectedHeader:  "Wed, 02 Jan 2019 03:04:05 GMT",
+		},
+		{
+			desc:            "modified since",
+			lastModified:    modified,
+			ifModifiedSince: "Wed, 02 Jan 2019 03:04:04 GMT",
+			expectedStatus:  http.StatusOK,
+			expectedHeader:  "Wed, 02 Jan 2019 03:04:05 GMT",
+			expectedBody:    "hello",
+		},
+		{
+			desc:            "invalid If-Modified-Since",
+			lastModified:    modified,
+			ifModifiedSince: "yesterday",
+			expectedStatus:  http.StatusOK,
+			expectedHeader:  "Wed, 02 Jan 2019 03:04:05 GMT",
+			expectedBody:    "hello",
+		},
+		{
+			desc:            "If-Modified-Since without last modification",
+			ifModifiedSince: "Wed, 02 Jan 2019 03:04:05 GMT",
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "hello",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			d := Data{
+				Head:            Head{Status: http.StatusOK},
+				LastModified:    test.lastModified,
+				IfModifiedSince: test.ifModifiedSince,
+			}
+			recorder := httptest.NewRecorder()
+			err := d.Render(recorder, []byte("hello"))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedHeader, recorder.Header().Get(LastModified))
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestData_Render_invalidValue(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		value interface{}
+	}{
+		{
+			desc:  "nil",
+			value: nil,
+		},
+		{
+			desc:  "string",
+			value: "hello",
+		},
+		{
+			desc:  "nil reader",
+			value: (*bytes.Reader)(nil),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			d := Data{Head: Head{Status: http.StatusOK}, LastModified: time.Now()}
+			recorder := httptest.NewRecorder()
+			err := d.Render(recorder, test.value)
+			require.Error(t, err)
+
+			assert.Empty(t, recorder.Header())
+			assert.Empty(t, recorder.Body.String())
+		})
+	}
+}
+
+// chunkedRecorder records the body chunks written between flushes.
+type chunkedRecorder struct {
+	*httptest.ResponseRecorder
+	chunks []string
+}
+
+func (c *chunkedRecorder) Flush() {
+	c.chunks = append(c.chunks, c.Body.String())
+	c.Body.Reset()
+}
+
+func TestData_Render_reader(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		engine         Data
+		expectedLength string
+		expectedChunks []string
+	}{
+		{
+			desc:           "default chunk size",
+			expectedChunks: []string{"hello world"},
+		},
+		{
+			desc:           "small chunks",
+			engine:         Data{ChunkSize: 4},
+			expectedChunks: []string{"hell", "o wo", "rld"},
+		},
+		{
+			desc:           "known length",
+			engine:         Data{ContentLength: 11},
+			expectedLength: "11",
+			expectedChunks: []string{"hello world"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			engine := test.engine
+			engine.Head.Status = http.StatusOK
+			recorder := &chunkedRecorder{ResponseRecorder: httptest.NewRecorder()}
+			err := engine.Render(recorder, bytes.NewReader([]byte("hello world")))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedChunks, recorder.chunks)
+			assert.Equal(t, test.expectedLength, recorder.Header().Get(ContentLength))
+		})
+	}
+}
+
+// failingReader returns its data, then an error.
+type failingReader struct {
+	data io.Reader
+}
+
+func (f failingReader) Read(p []byte) (int, error) {
+	n, err := f.data.Read(p)
+	if err == io.EOF {
+		return n, errors.New("read failed")
+	}
+	return n, err
+}
+
+func TestData_Render_readerError(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	err := Data{Head: Head{Status: http.StatusOK}}.Render(recorder, failingReader{data: bytes.NewReader([]byte("hello"))})
+	require.Error(t, err)
+
+	assert.Equal(t, "hello", recorder.Body.String())
+}
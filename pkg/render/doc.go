@@ -6,7 +6,7 @@
       "encoding/xml"
       "net/http"
 
-      "github.com/unrolled/render"  // or "gopkg.in/unrolled/render.v1"
+      "github.com/containous/traefik/pkg/render"
   )
 
   type ExampleXml struct {
@@ -0,0 +1,1591 @@
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/golang/protobuf/proto"
+	"github.com/tinylib/msgp/msgp"
+	"gopkg.in/yaml.v2"
+)
+
+// jsonpCallbackPattern matches safe JSONP callback names: JavaScript identifiers
+// optionally separated by dots (e.g. "jQuery.handlers.cb_1").
+var jsonpCallbackPattern = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z0-9_$]*(\.[a-zA-Z_$][a-zA-Z0-9_$]*)*$`)
+
+// Engine is the generic interface for all responses.
+//
+// Engines are meant to be built once and shared: Render must not modify the
+// engine or anything it references, so that concurrent renders are safe.
+type Engine interface {
+	Render(io.Writer, interface{}) error
+}
+
+// ContextEngine is implemented by engines which can stop rendering early when
+// the given context is cancelled, e.g. when a streaming client goes away.
+type ContextEngine interface {
+	Engine
+	RenderCtx(context.Context, io.Writer, interface{}) error
+}
+
+// Head defines the basic ContentType and Status fields, along with any extra Headers to set.
+type Head struct {
+	ContentType string
+	Status      int
+	Headers     http.Header
+	// Charset is appended to ContentType unless it already specifies one.
+	// Engines building a default ContentType use UTF-8 when it's empty.
+	Charset string
+	// Vary values are appended to the Vary header, keeping the ones already set.
+	Vary []string
+	// CacheControl and Expires set the corresponding headers when not empty.
+	CacheControl string
+	Expires      time.Time
+	// CORS, when set, adds the Access-Control-* headers to the response.
+	CORS *CORS
+}
+
+// addVary appends the values to the Vary header unless already listed.
+func addVary(header http.Header, values ...string) {
+	for _, value := range values {
+		found := false
+		for _, line := range header[Vary] {
+			for _, v := range strings.Split(line, ",") {
+				v = strings.TrimSpace(v)
+				if v == "*" || strings.EqualFold(v, value) {
+					found = true
+				}
+			}
+		}
+		if !found {
+			header.Add(Vary, value)
+		}
+	}
+}
+
+// Data built-in renderer.
+//
+// When LastModified is set, a Last-Modified header is written. Since engines
+// never see the request, the caller passes the request's If-Modified-Since
+// header value, and a 304 Not Modified is written without a body when it
+// covers LastModified.
+type Data struct {
+	Head
+	DetectContentType bool
+	Filename          string
+	Attachment        bool
+	LastModified      time.Time
+	IfModifiedSince   string
+	// ContentLength is the size of an io.Reader body when known, set as the
+	// Content-Length header when positive.
+	ContentLength int64
+	// ChunkSize is the size of the chunks an io.Reader body is copied by, the
+	// writer being flushed after each of them. Default is 32KB.
+	ChunkSize int
+}
+
+// HTML built-in renderer.
+//
+// Renders execute a clone of Templates, made once by Validate or on every
+// render otherwise, in which case Templates must not have been executed
+// directly (see html/template's Clone).
+type HTML struct {
+	Head
+	Name      string
+	Templates *template.Template
+	// Layout is the name of a template rendering Name through {{ yield }}.
+	Layout string
+	// Funcs are layered onto the templates for this render only, overriding
+	// funcs of the same name declared when the templates were parsed.
+	Funcs template.FuncMap
+	// Block is the name of a single template to render instead of Name,
+	// skipping any Layout, e.g. for HTML fragment responses.
+	Block string
+	// ReloadFunc, when set, is called on every render to parse the templates
+	// afresh, replacing Templates, so that edits show up without a restart.
+	// Meant for development only.
+	ReloadFunc func() (*template.Template, error)
+	// Nonce generates a random nonce for every render, available to the templates
+	// through {{ nonce }}. The nonce is allowed by the script-src directive of the
+	// Content-Security-Policy of Head.Headers, or by a "script-src 'nonce-...'"
+	// policy when there is none.
+	Nonce bool
+	// Minify strips comments and superfluous whitespace from the output with
+	// MinifyFunc, or a built-in minifier when it's nil.
+	Minify     bool
+	MinifyFunc func([]byte) ([]byte, error)
+	// AcceptCharset and Transcoders transcode the output like those of Text.
+	AcceptCharset string
+	Transcoders   map[string]Transcoder
+	// Lang renders the template named "<Name>.<Lang>", e.g. "home.fr-CA", when
+	// it exists, falling back to the base language, "home.fr", then to Name.
+	// Layouts are looked up alike. Translate then backs the {{ T "key" }}
+	// template func with the messages of Lang.
+	Lang      string
+	Translate func(lang, key string, args ...interface{}) string
+
+	clones *htmlClones
+}
+
+// htmlClones are the clones of Templates made by Validate: base is never
+// executed so that renders layering funcs can clone it, exec is executed by
+// the other renders.
+type htmlClones struct {
+	source *template.Template
+	base   *template.Template
+	exec   *template.Template
+}
+
+// JSON built-in renderer.
+type JSON struct {
+	Head
+	Indent        bool
+	UnEscapeHTML  bool
+	Prefix        []byte
+	PrefixString  string
+	SecurePrefix  bool
+	StreamingJSON bool
+	// SortKeys guarantees that every JSON object, including nested ones, is emitted
+	// in sorted key order. This round-trips the value through a generic representation,
+	// so it has a cost and is off by default.
+	SortKeys bool
+	// IndentPrefix and IndentValue are used when Indent is set, IndentValue defaults to two spaces.
+	IndentPrefix string
+	IndentValue  string
+	// AppendNewline controls the trailing new line, by default only indented output gets one.
+	AppendNewline Newline
+	// TimeFormat is the layout time.Time values are formatted with instead of RFC 3339,
+	// or one of TimeFormatUnix and TimeFormatUnixMilli.
+	TimeFormat string
+	// TagName is the struct tag to read field names and options from instead of `json`.
+	// Setting it switches to a slower reflection based encoding.
+	TagName string
+	// MarshalFunc, IndentFunc and NewEncoder replace encoding/json, e.g. with
+	// json-iterator or goccy/go-json. Output of MarshalFunc is indented with
+	// json.Indent when Indent is set and IndentFunc is nil.
+	MarshalFunc func(v interface{}) ([]byte, error)
+	IndentFunc  func(v interface{}, prefix, indent string) ([]byte, error)
+	NewEncoder  func(w io.Writer) JSONEncoder
+	// FlushEveryN flushes the writer after every N elements of a streamed slice
+	// or array, so that clients receive large responses progressively.
+	FlushEveryN int
+	// BOM writes a UTF-8 byte order mark before the prefix and the body.
+	BOM bool
+	// Fields limits the rendered objects to the named fields, e.g. for ?fields=id,name
+	// style APIs. Nested fields are named by dotted paths such as "author.name" and
+	// the elements of arrays are filtered alike. Values marshaling themselves are
+	// kept whole. Like TagName, it switches to the reflection based encoding.
+	Fields []string
+	// Envelope wraps the rendered value in an object under the given name, e.g.
+	// {"data": ...}, along with Meta under "meta" when it isn't nil.
+	Envelope string
+	Meta     interface{}
+	// Nil controls how a nil value, or a nil pointer, map or slice, is rendered.
+	// Default is NilAsNull.
+	Nil NilMode
+}
+
+// NilMode controls how the JSON engine renders nil values.
+type NilMode int
+
+const (
+	// NilAsNull renders nil as null.
+	NilAsNull NilMode = iota
+	// NilAsEmptyObject renders nil as {}.
+	NilAsEmptyObject
+	// NilAsEmptyArray renders nil as [].
+	NilAsEmptyArray
+	// NilAsNoContent writes a 204 No Content without a body.
+	NilAsNoContent
+)
+
+// JSONEncoder is a streaming JSON encoder, as implemented by *json.Encoder.
+type JSONEncoder interface {
+	Encode(v interface{}) error
+	SetIndent(prefix, indent string)
+}
+
+// Newline controls whether a new line terminates the rendered output.
+type Newline int
+
+const (
+	// NewlineDefault keeps the engine's default behavior.
+	NewlineDefault Newline = iota
+	// NewlineAlways always terminates the output with a new line.
+	NewlineAlways
+	// NewlineNever never terminates the output with a new line.
+	NewlineNever
+)
+
+// enabled resolves the mode given the engine's default behavior.
+func (n Newline) enabled(def bool) bool {
+	switch n {
+	case NewlineAlways:
+		return true
+	case NewlineNever:
+		return false
+	}
+	return def
+}
+
+// JSONP built-in renderer.
+type JSONP struct {
+	Head
+	Indent   bool
+	Callback string
+	// OmitSemicolon drops the semicolon terminating the last callback invocation.
+	OmitSemicolon bool
+	// AppendNewline controls the trailing new line, by default only indented output gets one.
+	AppendNewline Newline
+}
+
+// MsgPack built-in renderer, encoding with github.com/tinylib/msgp.
+//
+// Values implementing msgp.Marshaler, usually through msgp code generation, and
+// the values msgp supports natively are encoded by msgp. Others, such as plain
+// structs and maps, are encoded by reflection: struct fields are named after the
+// `msg` struct tag, falling back to the field name. When Streaming, values must
+// implement msgp.Encodable to be written without being buffered.
+type MsgPack struct {
+	Head
+	Prefix    []byte
+	Streaming bool
+}
+
+// Protobuf built-in renderer.
+type Protobuf struct {
+	Head
+	Deterministic bool
+}
+
+// Text built-in renderer.
+type Text struct {
+	Head
+	// AcceptCharset is the request's Accept-Charset header value. When it excludes
+	// UTF-8, the output is transcoded to the preferred charset among Transcoders,
+	// which are keyed by charset name, e.g. "ISO-8859-1".
+	AcceptCharset string
+	Transcoders   map[string]Transcoder
+}
+
+// TOML built-in renderer.
+type TOML struct {
+	Head
+	Indent bool
+	Prefix []byte
+}
+
+// XML built-in renderer.
+type XML struct {
+	Head
+	Indent       bool
+	Prefix       []byte
+	PrefixString string
+	XMLHeader    bool
+	RootElement  string
+	// StreamingXML encodes straight to the writer once the head is written.
+	// Like StreamingJSON, an encoding error can't change the status anymore.
+	StreamingXML bool
+	// BOM writes a UTF-8 byte order mark before anything else.
+	BOM bool
+	// IndentPrefix and IndentValue are used when Indent is set, IndentValue defaults to two spaces.
+	IndentPrefix string
+	IndentValue  string
+}
+
+// YAML built-in renderer.
+type YAML struct {
+	Head
+	Indent bool
+	Prefix []byte
+}
+
+// Write outputs the header content.
+func (h Head) Write(w http.ResponseWriter) {
+	for k, v := range h.Headers {
+		// Copy the values so the engine's Headers are never shared with the response.
+		w.Header()[http.CanonicalHeaderKey(k)] = append([]string(nil), v...)
+	}
+	contentType := h.ContentType
+	if h.Charset != "" && contentType != "" && !strings.Contains(strings.ToLower(contentType), "charset=") {
+		contentType += "; charset=" + h.Charset
+	}
+	w.Header().Set(ContentType, contentType)
+	addVary(w.Header(), h.Vary...)
+	if h.CacheControl != "" {
+		w.Header().Set(CacheControl, h.CacheControl)
+	}
+	if !h.Expires.IsZero() {
+		w.Header().Set(Expires, h.Expires.UTC().Format(http.TimeFormat))
+	}
+	if h.CORS != nil {
+		h.CORS.write(w.Header())
+	}
+	w.WriteHeader(h.Status)
+}
+
+// keepContentType makes the head keep a content type already set on the response,
+// e.g. by a middleware pinning a media type.
+func (h *Head) keepContentType(w http.ResponseWriter) {
+	if c := w.Header().Get(ContentType); c != "" {
+		h.ContentType = c
+	}
+}
+
+// setDefaultContentType sets ContentType to the given media type and charset if it's empty.
+func (h *Head) setDefaultContentType(mediaType string) {
+	if h.ContentType != "" {
+		return
+	}
+
+	charset := h.Charset
+	if charset == "" {
+		charset = defaultCharset
+	}
+	h.ContentType = mediaType + "; charset=" + charset
+}
+
+// Render a data response.
+func (d Data) Render(w io.Writer, v interface{}) error {
+	var r io.Reader
+	b, ok := v.([]byte)
+	if !ok {
+		if r, ok = v.(io.Reader); !ok {
+			return fmt.Errorf("render: Data expects []byte or io.Reader, got %T", v)
+		}
+		if isNil(r) {
+			return fmt.Errorf("render: Data cannot render a nil %T", v)
+		}
+	}
+
+	if hw, ok := w.(http.ResponseWriter); ok {
+		d.Head.keepContentType(hw)
+		if d.Head.ContentType == "" && d.DetectContentType {
+			if r != nil {
+				// Sniff the first bytes without losing them.
+				br := bufio.NewReaderSize(r, 512)
+				b, _ = br.Peek(512)
+				r = br
+			}
+			d.Head.ContentType = http.DetectContentType(b)
+		}
+		if d.Filename != "" {
+			disposition := "inline"
+			if d.Attachment {
+				disposition = "attachment"
+			}
+			hw.Header().Set(ContentDisposition, contentDisposition(disposition, d.Filename))
+		}
+		if !d.LastModified.IsZero() {
+			hw.Header().Set(LastModified, d.LastModified.UTC().Format(http.TimeFormat))
+			if d.notModified() {
+				d.Head.Status = http.StatusNotModified
+				d.Head.Write(hw)
+				return nil
+			}
+		}
+		if r == nil {
+			hw.Header().Set(ContentLength, strconv.Itoa(len(b)))
+		} else if d.ContentLength > 0 {
+			hw.Header().Set(ContentLength, strconv.FormatInt(d.ContentLength, 10))
+		}
+		d.Head.Write(hw)
+	}
+
+	if r != nil {
+		return d.copy(w, r)
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// copy copies r to w chunk by chunk, flushing w after each chunk so that large
+// bodies are streamed rather than buffered. The reader isn't closed.
+func (d Data) copy(w io.Writer, r io.Reader) error {
+	size := d.ChunkSize
+	if size <= 0 {
+		size = 32 * 1024
+	}
+	chunk := make([]byte, size)
+	for {
+		n, err := r.Read(chunk)
+		if n > 0 {
+			if _, werr := w.Write(chunk[:n]); werr != nil {
+				return werr
+			}
+			flush(w)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// notModified reports whether IfModifiedSince covers LastModified, HTTP dates
+// having a one second resolution.
+func (d Data) notModified() bool {
+	if d.IfModifiedSince == "" {
+		return false
+	}
+	t, err := http.ParseTime(d.IfModifiedSince)
+	if err != nil {
+		return false
+	}
+	return !d.LastModified.Truncate(time.Second).After(t)
+}
+
+// contentDisposition builds a Content-Disposition header value as described in RFC 6266.
+// Non-ASCII filenames get an ASCII fallback along with the RFC 5987 `filename*` form.
+func contentDisposition(disposition, filename string) string {
+	var fallback strings.Builder
+	ascii := true
+	for _, r := range filename {
+		switch {
+		case r == '"' || r == '\\':
+			fallback.WriteByte('\\')
+			fallback.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			fallback.WriteByte('_')
+		case r > 0x7f:
+			ascii = false
+			fallback.WriteByte('_')
+		default:
+			fallback.WriteRune(r)
+		}
+	}
+
+	value := disposition + `; filename="` + fallback.String() + `"`
+	if ascii {
+		return value
+	}
+
+	var encoded strings.Builder
+	for _, c := range []byte(filename) {
+		if isAttrChar(c) {
+			encoded.WriteByte(c)
+		} else {
+			fmt.Fprintf(&encoded, "%%%02X", c)
+		}
+	}
+	return value + "; filename*=UTF-8''" + encoded.String()
+}
+
+// isAttrChar reports whether c can be used unencoded in an RFC 5987 ext-value.
+func isAttrChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$&+-.^_`|~", c) != -1
+}
+
+// Render a HTML response.
+func (h HTML) Render(w io.Writer, binding interface{}) error {
+	binding = h.Head.errorPayload(binding)
+
+	if h.Block != "" {
+		h.Name, h.Layout = h.Block, ""
+	}
+
+	if h.ReloadFunc != nil {
+		t, err := h.ReloadFunc()
+		if err != nil {
+			return err
+		}
+		h.Templates = t
+	}
+	if h.Templates == nil {
+		return errors.New("render: HTML has no templates")
+	}
+
+	if h.Lang != "" {
+		h.Name = h.localized(h.Name)
+		if h.Layout != "" {
+			h.Layout = h.localized(h.Layout)
+		}
+	}
+
+	var nonce string
+	if h.Nonce {
+		var err error
+		if nonce, err = newNonce(); err != nil {
+			return err
+		}
+
+		// Copy the headers so the engine's Headers are never modified.
+		headers := make(http.Header, len(h.Head.Headers)+1)
+		for k, v := range h.Head.Headers {
+			k = http.CanonicalHeaderKey(k)
+			headers[k] = append(headers[k], v...)
+		}
+
+		// Browsers enforce every policy, so the nonce goes into the existing ones
+		// rather than into a policy of its own.
+		if policies := headers[ContentSecurityPolicy]; len(policies) > 0 {
+			for i, policy := range policies {
+				policies[i] = addNonce(policy, nonce)
+			}
+		} else {
+			headers.Set(ContentSecurityPolicy, "script-src 'nonce-"+nonce+"'")
+		}
+		h.Head.Headers = headers
+	}
+
+	// Execute a clone even without funcs to layer, since Templates couldn't be
+	// cloned for the Layout renders sharing it anymore once executed.
+	var t *template.Template
+	var err error
+	if h.validated() && !h.layersFuncs() {
+		t = h.clones.exec
+	} else if t, err = h.cloneTemplates(binding, nonce); err != nil {
+		return err
+	}
+	name := h.Name
+	if h.Layout != "" {
+		name = h.Layout
+	}
+
+	// Retrieve a buffer from the pool to write to.
+	out := bufPool.Get()
+	err = executeTemplate(t, out, name, binding)
+	if err != nil {
+		return err
+	}
+
+	result := out.Bytes()
+	if h.Minify {
+		minify := h.MinifyFunc
+		if minify == nil {
+			minify = minifyHTML
+		}
+		if result, err = minify(result); err != nil {
+			return err
+		}
+	}
+
+	if hw, ok := w.(http.ResponseWriter); ok {
+		h.Head.setDefaultContentType(ContentHTML)
+		if len(h.Transcoders) > 0 {
+			if result, err = h.Head.transcode(result, h.AcceptCharset, h.Transcoders); err != nil {
+				return err
+			}
+		}
+		h.Head.Write(hw)
+	}
+	_, err = w.Write(result)
+
+	// Return the buffer to the pool.
+	bufPool.Put(out)
+	return err
+}
+
+// Validate checks that the templates to render exist, so that a misspelled
+// Name, Layout or Block is caught when the engine is built rather than on the
+// first render. Templates are loaded through ReloadFunc when it's set.
+//
+// Without ReloadFunc, Validate also clones Templates once for the renders of h
+// to execute, instead of cloning them on every render, which html/template
+// refuses as soon as they have been executed elsewhere, e.g. by Render.HTML.
+func (h *HTML) Validate() error {
+	t := h.Templates
+	if h.ReloadFunc != nil {
+		var err error
+		if t, err = h.ReloadFunc(); err != nil {
+			return err
+		}
+	}
+	if t == nil {
+		return errors.New("render: HTML has no templates")
+	}
+
+	names := []string{h.Name, h.Layout}
+	if h.Block != "" {
+		names = []string{h.Block}
+	}
+	for _, name := range names {
+		if name != "" && t.Lookup(name) == nil {
+			return fmt.Errorf("render: HTML template %q not found, defined templates are %s", name, strings.TrimPrefix(t.DefinedTemplates(), "; defined templates are: "))
+		}
+	}
+	if h.Name == "" && h.Block == "" {
+		return errors.New("render: HTML has no template name")
+	}
+	if h.ReloadFunc != nil {
+		return nil
+	}
+
+	base, err := t.Clone()
+	if err != nil {
+		return err
+	}
+	exec, err := base.Clone()
+	if err != nil {
+		return err
+	}
+	h.clones = &htmlClones{source: t, base: base, exec: exec}
+	return nil
+}
+
+// cloneTemplates clones the templates and layers the render funcs onto them,
+// so that concurrent renders never share the funcs of one another.
+func (h HTML) cloneTemplates(binding interface{}, nonce string) (*template.Template, error) {
+	src := h.Templates
+	if h.validated() {
+		src = h.clones.base
+	}
+	t, err := src.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(h.Funcs) > 0 {
+		t.Funcs(h.Funcs)
+	}
+	if h.Nonce {
+		t.Funcs(template.FuncMap{
+			"nonce": func() string {
+				return nonce
+			},
+		})
+	}
+	if h.translates() {
+		t.Funcs(template.FuncMap{
+			"T": func(key string, args ...interface{}) string {
+				return h.Translate(h.Lang, key, args...)
+			},
+		})
+	}
+	if h.Layout == "" {
+		return t, nil
+	}
+
+	t.Funcs(template.FuncMap{
+		"yield": func() (template.HTML, error) {
+			buf := new(bytes.Buffer)
+			err := t.ExecuteTemplate(buf, h.Name, binding)
+			// Return safe HTML here since we are rendering our own template.
+			return template.HTML(buf.String()), err
+		},
+		"current": func() (string, error) {
+			return h.Name, nil
+		},
+	})
+	return t, nil
+}
+
+// localized returns the name of the template for Lang, or name when there is none.
+func (h HTML) localized(name string) string {
+	if h.Templates.Lookup(name+"."+h.Lang) != nil {
+		return name + "." + h.Lang
+	}
+	if i := strings.IndexAny(h.Lang, "-_"); i != -1 && h.Templates.Lookup(name+"."+h.Lang[:i]) != nil {
+		return name + "." + h.Lang[:i]
+	}
+	return name
+}
+
+// validated reports whether the clones made by Validate are those of Templates,
+// which a ReloadFunc or a change of Templates since replaces.
+func (h HTML) validated() bool {
+	return h.clones != nil && h.clones.source == h.Templates
+}
+
+// layersFuncs reports whether the render layers funcs onto the templates.
+func (h HTML) layersFuncs() bool {
+	return h.Layout != "" || len(h.Funcs) > 0 || h.Nonce || h.translates()
+}
+
+func (h HTML) translates() bool {
+	return h.Lang != "" && h.Translate != nil
+}
+
+// executeTemplate executes the named template, turning a panic during the
+// execution into an error so that a bad template can't crash the server.
+func executeTemplate(t *template.Template, w io.Writer, name string, binding interface{}) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("render: template %q panicked: %v", name, p)
+		}
+	}()
+	return t.ExecuteTemplate(w, name, binding)
+}
+
+// addNonce allows the nonce in the script directives of a Content-Security-Policy
+// header value, adding a script-src directive from default-src when there is
+// none. Policies which don't restrict scripts are left as is.
+func addNonce(value, nonce string) string {
+	source := "'nonce-" + nonce + "'"
+
+	// Multiple policies may be joined by commas in a single value.
+	policies := strings.Split(value, ",")
+	for i, policy := range policies {
+		var directives [][]string
+		var fallback []string
+		scripts := false
+		for _, directive := range strings.Split(policy, ";") {
+			fields := strings.Fields(directive)
+			if len(fields) == 0 {
+				continue
+			}
+			switch strings.ToLower(fields[0]) {
+			case "script-src", "script-src-elem":
+				fields = withSource(fields, source)
+				scripts = true
+			case "default-src":
+				fallback = fields
+			}
+			directives = append(directives, fields)
+		}
+		if !scripts && fallback != nil {
+			directives = append(directives, withSource(append([]string{"script-src"}, fallback[1:]...), source))
+		}
+
+		joined := make([]string, len(directives))
+		for j, fields := range directives {
+			joined[j] = strings.Join(fields, " ")
+		}
+		policies[i] = strings.Join(joined, "; ")
+	}
+	return strings.Join(policies, ", ")
+}
+
+// withSource appends source to the sources of a directive, dropping 'none'.
+func withSource(directive []string, source string) []string {
+	fields := []string{directive[0]}
+	for _, f := range directive[1:] {
+		if f != "'none'" {
+			fields = append(fields, f)
+		}
+	}
+	return append(fields, source)
+}
+
+// newNonce returns a base64 encoded random nonce for Content-Security-Policy.
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// Render a JSON response.
+func (j JSON) Render(w io.Writer, v interface{}) error {
+	return j.RenderCtx(context.Background(), w, v)
+}
+
+// RenderCtx renders a JSON response, the streaming path checks ctx between encoded slice elements.
+// When streaming, a receive channel is encoded as an array flushed as its elements arrive,
+// until the channel is closed or ctx is done. TagName, TimeFormat, Fields and SortKeys apply
+// to each element, while an Envelope can't wrap a channel.
+func (j JSON) RenderCtx(ctx context.Context, w io.Writer, v interface{}) error {
+	v = j.Head.errorPayload(v)
+
+	if j.Nil != NilAsNull && isNil(v) {
+		switch j.Nil {
+		case NilAsEmptyObject:
+			v = struct{}{}
+		case NilAsEmptyArray:
+			v = []struct{}{}
+		case NilAsNoContent:
+			if hw, ok := w.(http.ResponseWriter); ok {
+				j.Head.Status = http.StatusNoContent
+				j.Head.Write(noContentResponseWriter{hw})
+			}
+			return nil
+		}
+	}
+
+	j.Prefix = prefixBytes(j.Prefix, j.PrefixString)
+	if j.SecurePrefix {
+		if len(j.Prefix) > 0 {
+			return errors.New("render: JSON SecurePrefix and Prefix are mutually exclusive")
+		}
+		j.Prefix = []byte(SecureJSONPrefix)
+	}
+	if j.BOM {
+		j.Prefix = append([]byte(utf8BOM), j.Prefix...)
+	}
+
+	// Channels are converted element by element as they are received.
+	if reflect.ValueOf(v).Kind() == reflect.Chan {
+		if j.Envelope != "" {
+			return errors.New("render: JSON Envelope cannot wrap a channel")
+		}
+	} else {
+		var err error
+		if v, err = j.convert(v); err != nil {
+			return err
+		}
+	}
+
+	if j.StreamingJSON {
+		return j.renderStreamingJSON(ctx, w, v)
+	}
+
+	// Nothing to post-process, let the encoder write straight to w.
+	if !j.Indent && !j.UnEscapeHTML && len(j.Prefix) == 0 && j.MarshalFunc == nil && j.NewEncoder == nil {
+		return json.NewEncoder(&jsonHeadWriter{w: w, head: j.Head, newline: j.AppendNewline.enabled(false)}).Encode(v)
+	}
+
+	// Retrieve a buffer from the pool to encode into.
+	buf := bufPool.Get()
+	defer bufPool.Put(buf)
+
+	result, err := j.marshal(buf, v)
+	if err != nil {
+		return err
+	}
+
+	// By default only indented output is terminated with a new line.
+	if j.AppendNewline.enabled(j.Indent) {
+		result = append(result, '\n')
+	}
+
+	// Unescape HTML if needed.
+	if j.UnEscapeHTML {
+		out := bufPool.Get()
+		defer bufPool.Put(out)
+
+		unescapeHTML(out, result)
+		result = out.Bytes()
+	}
+
+	// JSON marshaled fine, write out the result.
+	if hw, ok := w.(http.ResponseWriter); ok {
+		hw.Header().Set(ContentLength, strconv.Itoa(len(j.Prefix)+len(result)))
+		j.Head.keepContentType(hw)
+		j.Head.setDefaultContentType(ContentJSON)
+		j.Head.Write(hw)
+	}
+	ew := &errWriter{w: w}
+	ew.Write(j.Prefix)
+	ew.Write(result)
+	return ew.err
+}
+
+// convert applies TagName, TimeFormat, Fields, Envelope and SortKeys to v.
+func (j JSON) convert(v interface{}) (interface{}, error) {
+	if j.TagName != "" || j.TimeFormat != "" || len(j.Fields) > 0 {
+		tagName := j.TagName
+		if tagName == "" {
+			tagName = "json"
+		}
+		var err error
+		if v, err = (jsonConverter{tagName: tagName, timeFormat: j.TimeFormat}).convert(v); err != nil {
+			return nil, err
+		}
+	}
+	if len(j.Fields) > 0 {
+		v = newFieldSet(j.Fields).filter(v)
+	}
+
+	if j.Envelope != "" {
+		envelope := jsonObject{{name: j.Envelope, value: v}}
+		if j.Meta != nil {
+			envelope = append(envelope, jsonField{name: "meta", value: j.Meta})
+		}
+		v = envelope
+	}
+
+	if j.SortKeys {
+		return sortedJSONValue(v)
+	}
+	return v, nil
+}
+
+func (j JSON) renderStreamingJSON(ctx context.Context, w io.Writer, v interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Chan && rv.Type().ChanDir()&reflect.RecvDir == 0 {
+		return fmt.Errorf("render: JSON cannot receive from %T", v)
+	}
+
+	if hw, ok := w.(http.ResponseWriter); ok {
+		j.Head.keepContentType(hw)
+		j.Head.setDefaultContentType(ContentJSON)
+		j.Head.Write(hw)
+	}
+	if len(j.Prefix) > 0 {
+		if _, err := w.Write(j.Prefix); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case rv.Kind() == reflect.Chan:
+		// Elements of a channel are sent as soon as they arrive unless told otherwise.
+		flushEvery := j.FlushEveryN
+		if flushEvery <= 0 {
+			flushEvery = 1
+		}
+		if err := j.encodeArray(ctx, w, rv, flushEvery); err != nil {
+			return err
+		}
+	case (ctx.Done() == nil && j.FlushEveryN <= 0) || !encodesElements(rv) || (rv.Kind() == reflect.Slice && rv.IsNil()):
+		// Without a cancellable context or periodic flushes there is nothing to do
+		// between elements, encode in one go. So are values which aren't encoded
+		// as the array of their elements.
+		enc := j.newEncoder(w)
+		if j.Indent {
+			enc.SetIndent(j.indent())
+		}
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	default:
+		if err := j.encodeArray(ctx, w, rv, j.FlushEveryN); err != nil {
+			return err
+		}
+	}
+
+	// Push the encoded bytes to the client right away if the writer buffers.
+	flush(w)
+	return nil
+}
+
+// marshal encodes v into buf with the configured marshaler, without a trailing new line.
+func (j JSON) marshal(buf *bytes.Buffer, v interface{}) ([]byte, error) {
+	prefix, indent := j.indent()
+
+	// Results of the funcs are copied into buf, so that appending to them never
+	// touches memory the funcs may share between calls.
+	switch {
+	case j.Indent && j.IndentFunc != nil:
+		b, err := j.IndentFunc(v, prefix, indent)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+		return buf.Bytes(), nil
+	case j.MarshalFunc != nil:
+		b, err := j.MarshalFunc(v)
+		if err != nil {
+			return nil, err
+		}
+		if !j.Indent {
+			buf.Write(b)
+		} else if err := json.Indent(buf, b, prefix, indent); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	enc := json.NewEncoder(buf)
+	if j.Indent {
+		enc.SetIndent(prefix, indent)
+	}
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	// The encoder terminates the value with a new line.
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// newEncoder returns the configured streaming encoder, defaulting to encoding/json.
+func (j JSON) newEncoder(w io.Writer) JSONEncoder {
+	if j.NewEncoder != nil {
+		return j.NewEncoder(w)
+	}
+	return json.NewEncoder(w)
+}
+
+// jsonHeadWriter writes the head right before the single Write of a json.Encoder,
+// once the size of the value is known, and drops the new line terminating it unless newline is set.
+// Nothing is written when the encoder fails.
+type jsonHeadWriter struct {
+	w       io.Writer
+	head    Head
+	newline bool
+}
+
+func (jw *jsonHeadWriter) Write(p []byte) (int, error) {
+	b := p
+	if !jw.newline {
+		b = bytes.TrimSuffix(p, []byte("\n"))
+	}
+	if hw, ok := jw.w.(http.ResponseWriter); ok {
+		hw.Header().Set(ContentLength, strconv.Itoa(len(b)))
+		jw.head.keepContentType(hw)
+		jw.head.setDefaultContentType(ContentJSON)
+		jw.head.Write(hw)
+	}
+
+	n, err := jw.w.Write(b)
+	if err == nil {
+		n = len(p)
+	}
+	return n, err
+}
+
+// encodeArray encodes the elements of the slice, array or channel rv one by one
+// as a JSON array, writing the same bytes as encoding rv in one go. It aborts
+// with the context error as soon as ctx is done and flushes w after every
+// flushEvery elements when positive.
+func (j JSON) encodeArray(ctx context.Context, w io.Writer, rv reflect.Value, flushEvery int) error {
+	// Retrieve a buffer from the pool to encode the elements into.
+	buf := bufPool.Get()
+	defer bufPool.Put(buf)
+
+	// When indenting, elements are nested one level into the array and each
+	// start on a new line.
+	enc := j.newEncoder(buf)
+	var lead, tail string
+	if j.Indent {
+		prefix, indent := j.indent()
+		enc.SetIndent(prefix+indent, indent)
+		lead, tail = "\n"+prefix+indent, "\n"+prefix
+	}
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	n := 0
+	err := eachElement(ctx, rv, func(e interface{}) error {
+		// Unlike slices and arrays, channels weren't converted as a whole.
+		if rv.Kind() == reflect.Chan {
+			var err error
+			if e, err = j.convert(e); err != nil {
+				return err
+			}
+		}
+
+		buf.Reset()
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+
+		sep := lead
+		if n > 0 {
+			sep = "," + lead
+		}
+		if _, err := io.WriteString(w, sep); err != nil {
+			return err
+		}
+		// Drop the new line terminating each encoded element.
+		if _, err := w.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))); err != nil {
+			return err
+		}
+		n++
+		if flushEvery > 0 && n%flushEvery == 0 {
+			flush(w)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		tail = ""
+	}
+	_, err = io.WriteString(w, tail+"]\n")
+	return err
+}
+
+// encodesElements reports whether rv is a slice or array encoded as the JSON
+// array of its elements: its type doesn't marshal itself, and it doesn't hold
+// bytes, which encoding/json encodes as a base64 string.
+func encodesElements(rv reflect.Value) bool {
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return false
+	}
+	t := rv.Type()
+	if t.Elem().Kind() == reflect.Uint8 {
+		return false
+	}
+	for _, m := range []reflect.Type{jsonMarshalerType, textMarshalerType} {
+		if t.Implements(m) || reflect.PtrTo(t).Implements(m) {
+			return false
+		}
+	}
+	return true
+}
+
+// isNil reports whether v is nil or a nil pointer, map, slice or interface.
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// indent returns the prefix and indent strings to use, defaulting to two spaces.
+func (j JSON) indent() (string, string) {
+	if j.IndentValue == "" {
+		return j.IndentPrefix, "  "
+	}
+	return j.IndentPrefix, j.IndentValue
+}
+
+// unescapeHTML writes src to dst, replacing the \u003c, \u003e and \u0026 escapes
+// with "<", ">" and "&" in a single pass. Every other escape sequence is skipped as
+// a whole, so an escaped backslash followed by the literal text "u003c" is left untouched.
+func unescapeHTML(dst *bytes.Buffer, src []byte) {
+	dst.Grow(len(src))
+
+	last := 0
+	for i := 0; i+1 < len(src); i++ {
+		if src[i] != '\\' {
+			continue
+		}
+		if i+6 > len(src) || src[i+1] != 'u' || src[i+2] != '0' || src[i+3] != '0' {
+			// Skip the escaped character, it can't start another escape sequence.
+			i++
+			continue
+		}
+
+		var c byte
+		switch {
+		case src[i+4] == '3' && src[i+5] == 'c':
+			c = '<'
+		case src[i+4] == '3' && src[i+5] == 'e':
+			c = '>'
+		case src[i+4] == '2' && src[i+5] == '6':
+			c = '&'
+		default:
+			continue
+		}
+
+		dst.Write(src[last:i])
+		dst.WriteByte(c)
+		i += 5
+		last = i + 1
+	}
+	dst.Write(src[last:])
+}
+
+// sortedJSONValue converts v into its generic JSON representation (maps, slices and
+// json.Number values). Marshaling the result always emits object keys in sorted order.
+func sortedJSONValue(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var sorted interface{}
+	if err := dec.Decode(&sorted); err != nil {
+		return nil, err
+	}
+	return sorted, nil
+}
+
+// prefixBytes returns prefix, or s as bytes when prefix is nil.
+func prefixBytes(prefix []byte, s string) []byte {
+	if prefix == nil && s != "" {
+		return []byte(s)
+	}
+	return prefix
+}
+
+// Render a JSONP response.
+func (j JSONP) Render(w io.Writer, v interface{}) error {
+	v = j.Head.errorPayload(v)
+
+	// Callback may hold a comma-separated list, each one is invoked with the same payload.
+	callbacks := strings.Split(j.Callback, ",")
+	for i, callback := range callbacks {
+		callbacks[i] = strings.TrimSpace(callback)
+		if !jsonpCallbackPattern.MatchString(callbacks[i]) {
+			return fmt.Errorf("render: invalid JSONP callback name %q", callbacks[i])
+		}
+	}
+
+	var result []byte
+	var err error
+
+	if j.Indent {
+		result, err = json.MarshalIndent(v, "", "  ")
+	} else {
+		result, err = json.Marshal(v)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Assemble the output in a pooled buffer so it's written at once.
+	buf := bufPool.Get()
+	defer bufPool.Put(buf)
+
+	for i, callback := range callbacks {
+		buf.WriteString(callback)
+		buf.WriteByte('(')
+		buf.Write(result)
+		if j.OmitSemicolon && i == len(callbacks)-1 {
+			buf.WriteByte(')')
+		} else {
+			buf.WriteString(");")
+		}
+	}
+
+	// By default only indented output is terminated with a new line.
+	if j.AppendNewline.enabled(j.Indent) {
+		buf.WriteByte('\n')
+	}
+
+	// JSON marshaled fine, write out the result.
+	if hw, ok := w.(http.ResponseWriter); ok {
+		j.Head.keepContentType(hw)
+		j.Head.setDefaultContentType(ContentJSONP)
+		hw.Header().Set(ContentLength, strconv.Itoa(buf.Len()))
+		j.Head.Write(hw)
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// Render a MessagePack response.
+func (m MsgPack) Render(w io.Writer, v interface{}) error {
+	v = m.Head.errorPayload(v)
+
+	if m.Streaming {
+		return m.renderStreamingMsgPack(w, v)
+	}
+
+	result, err := appendMsgPack(nil, v)
+	if err != nil {
+		return err
+	}
+
+	// MessagePack marshaled fine, write out the result.
+	if hw, ok := w.(http.ResponseWriter); ok {
+		if m.Head.ContentType == "" {
+			m.Head.ContentType = ContentMsgPack
+		}
+		m.Head.Write(hw)
+	}
+	ew := &errWriter{w: w}
+	ew.Write(m.Prefix)
+	ew.Write(result)
+	return ew.err
+}
+
+func (m MsgPack) renderStreamingMsgPack(w io.Writer, v interface{}) error {
+	if _, ok := v.(msgp.Encodable); !ok {
+		// Only encodable values are streamed, others are buffered.
+		m.Streaming = false
+		return m.Render(w, v)
+	}
+
+	if hw, ok := w.(http.ResponseWriter); ok {
+		if m.Head.ContentType == "" {
+			m.Head.ContentType = ContentMsgPack
+		}
+		m.Head.Write(hw)
+	}
+	if len(m.Prefix) > 0 {
+		if _, err := w.Write(m.Prefix); err != nil {
+			return err
+		}
+	}
+
+	mw := msgp.NewWriter(w)
+	if err := mw.WriteIntf(v); err != nil {
+		return err
+	}
+	return mw.Flush()
+}
+
+// Render a Protobuf response.
+func (p Protobuf) Render(w io.Writer, v interface{}) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("render: Protobuf expects proto.Message, got %T", v)
+	}
+
+	buf := proto.NewBuffer(nil)
+	buf.SetDeterministic(p.Deterministic)
+	if err := buf.Marshal(m); err != nil {
+		return err
+	}
+
+	// Protobuf marshaled fine, write out the result.
+	if hw, ok := w.(http.ResponseWriter); ok {
+		if p.Head.ContentType == "" {
+			p.Head.ContentType = ContentProtobuf
+		}
+		p.Head.Write(hw)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Render a text response.
+func (t Text) Render(w io.Writer, v interface{}) error {
+	v = t.Head.errorPayload(v)
+
+	// Strings are kept as such, so that writers implementing io.StringWriter
+	// can write them without a copy.
+	var result []byte
+	var text string
+	switch s := v.(type) {
+	case string:
+		text = s
+	case []byte:
+		result = s
+	case fmt.Stringer:
+		text = s.String()
+	default:
+		return fmt.Errorf("render: Text expects string, []byte or fmt.Stringer, got %T", v)
+	}
+	isString := result == nil
+
+	if hw, ok := w.(http.ResponseWriter); ok {
+		t.Head.keepContentType(hw)
+		t.Head.setDefaultContentType(ContentText)
+		if len(t.Transcoders) > 0 {
+			if isString {
+				result, isString = []byte(text), false
+			}
+			var err error
+			if result, err = t.Head.transcode(result, t.AcceptCharset, t.Transcoders); err != nil {
+				return err
+			}
+		}
+		t.Head.Write(hw)
+	}
+
+	if isString {
+		_, err := io.WriteString(w, text)
+		return err
+	}
+	_, err := w.Write(result)
+	return err
+}
+
+// Render a TOML response.
+func (t TOML) Render(w io.Writer, v interface{}) error {
+	v = t.Head.errorPayload(v)
+
+	// Retrieve a buffer from the pool to encode into.
+	out := bufPool.Get()
+	defer bufPool.Put(out)
+
+	enc := toml.NewEncoder(out)
+	if !t.Indent {
+		enc.Indent = ""
+	}
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+
+	// TOML encoded fine, write out the result.
+	if hw, ok := w.(http.ResponseWriter); ok {
+		t.Head.setDefaultContentType(ContentTOML)
+		t.Head.Write(hw)
+	}
+	ew := &errWriter{w: w}
+	ew.Write(t.Prefix)
+	ew.Write(out.Bytes())
+	return ew.err
+}
+
+// Render an XML response.
+func (x XML) Render(w io.Writer, v interface{}) error {
+	v = x.Head.errorPayload(v)
+
+	x.Prefix = prefixBytes(x.Prefix, x.PrefixString)
+
+	if x.RootElement == "" && reflect.Indirect(reflect.ValueOf(v)).Kind() == reflect.Map {
+		return fmt.Errorf("render: XML cannot marshal %T without a RootElement", v)
+	}
+
+	if x.StreamingXML {
+		return x.renderStreamingXML(w, v)
+	}
+
+	// Retrieve a buffer from the pool to encode into.
+	buf := bufPool.Get()
+	defer bufPool.Put(buf)
+
+	enc := xml.NewEncoder(buf)
+	if x.Indent {
+		enc.Indent(x.indent())
+	}
+
+	var err error
+	if x.RootElement != "" {
+		err = encodeXMLRoot(enc, v, x.RootElement)
+	} else {
+		err = enc.Encode(v)
+	}
+	if err != nil {
+		return err
+	}
+	if x.Indent {
+		buf.WriteByte('\n')
+	}
+	result := buf.Bytes()
+
+	// XML marshaled fine, write out the result.
+	if hw, ok := w.(http.ResponseWriter); ok {
+		length := len(x.Prefix) + len(result)
+		if x.XMLHeader {
+			length += len(xml.Header)
+		}
+		if x.BOM {
+			length += len(utf8BOM)
+		}
+		hw.Header().Set(ContentLength, strconv.Itoa(length))
+		x.Head.keepContentType(hw)
+		x.Head.setDefaultContentType(ContentXML)
+		x.Head.Write(hw)
+	}
+	ew := &errWriter{w: w}
+	if x.BOM {
+		ew.Write([]byte(utf8BOM))
+	}
+	if x.XMLHeader {
+		ew.Write([]byte(xml.Header))
+	}
+	ew.Write(x.Prefix)
+	ew.Write(result)
+	return ew.err
+}
+
+// Render a YAML response.
+func (y YAML) Render(w io.Writer, v interface{}) error {
+	v = y.Head.errorPayload(v)
+
+	result, err := marshalYAML(v)
+	if err != nil {
+		return err
+	}
+
+	// The YAML encoder always terminates its output with a new line, only keep it when indenting.
+	if !y.Indent {
+		result = bytes.TrimSuffix(result, []byte("\n"))
+	}
+
+	// YAML marshaled fine, write out the result.
+	if hw, ok := w.(http.ResponseWriter); ok {
+		y.Head.keepContentType(hw)
+		y.Head.setDefaultContentType(ContentYAML)
+		y.Head.Write(hw)
+	}
+	ew := &errWriter{w: w}
+	ew.Write(y.Prefix)
+	ew.Write(result)
+	return ew.err
+}
+
+// marshalYAML marshals v to YAML, turning the panic the YAML encoder raises
+// for the types it can't marshal into an error.
+func marshalYAML(v interface{}) (result []byte, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			result, err = nil, fmt.Errorf("render: marshaling %T to YAML: %v", v, p)
+		}
+	}()
+	return yaml.Marshal(v)
+}
+
+func (x XML) renderStreamingXML(w io.Writer, v interface{}) error {
+	if hw, ok := w.(http.ResponseWriter); ok {
+		x.Head.keepContentType(hw)
+		x.Head.setDefaultContentType(ContentXML)
+		x.Head.Write(hw)
+	}
+	ew := &errWriter{w: w}
+	if x.BOM {
+		ew.Write([]byte(utf8BOM))
+	}
+	if x.XMLHeader {
+		ew.Write([]byte(xml.Header))
+	}
+	ew.Write(x.Prefix)
+	if ew.err != nil {
+		return ew.err
+	}
+
+	enc := xml.NewEncoder(w)
+	if x.Indent {
+		enc.Indent(x.indent())
+	}
+	if x.RootElement != "" {
+		return encodeXMLRoot(enc, v, x.RootElement)
+	}
+	return enc.Encode(v)
+}
+
+// indent returns the prefix and indent strings to use, defaulting to two spaces.
+func (x XML) indent() (string, string) {
+	if x.IndentValue == "" {
+		return x.IndentPrefix, "  "
+	}
+	return x.IndentPrefix, x.IndentValue
+}
+
+// encodeXMLRoot encodes v wrapped in an element named root. Slices and arrays
+// produce one child per element, maps with string keys produce one <key>value</key>
+// child per entry in sorted key order.
+func encodeXMLRoot(enc *xml.Encoder, v interface{}, root string) error {
+	start := xml.StartElement{Name: xml.Name{Local: root}}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if err := enc.Encode(rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("render: XML maps must have string keys, got %T", v)
+		}
+
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, key := range keys {
+			child := xml.StartElement{Name: xml.Name{Local: key.String()}}
+			if err := enc.EncodeElement(rv.MapIndex(key).Interface(), child); err != nil {
+				return err
+			}
+		}
+	case reflect.Invalid:
+		// Nothing to wrap, emit an empty root element.
+	default:
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+
+	if err := enc.EncodeToken(start.End()); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
@@ -0,0 +1,190 @@
+package render
+
+import (
+	"html/template"
+	"net/http"
+)
+
+// EngineOption configures an engine built by one of the New* engine constructors,
+// e.g. NewJSON(WithIndent(), WithPrefix(p)). Options which don't apply to an
+// engine are ignored by its constructor.
+type EngineOption func(*engineOptions)
+
+type engineOptions struct {
+	head         Head
+	indent       bool
+	layout       string
+	prefix       []byte
+	streaming    bool
+	unEscapeHTML bool
+}
+
+// WithStatus sets the HTTP status of the response.
+func WithStatus(status int) EngineOption {
+	return func(o *engineOptions) {
+		o.head.Status = status
+	}
+}
+
+// WithContentType overrides the engine's default content type.
+func WithContentType(contentType string) EngineOption {
+	return func(o *engineOptions) {
+		o.head.ContentType = contentType
+	}
+}
+
+// WithCharset sets the charset of the content type.
+func WithCharset(charset string) EngineOption {
+	return func(o *engineOptions) {
+		o.head.Charset = charset
+	}
+}
+
+// WithHeader adds an extra header to the response.
+func WithHeader(key, value string) EngineOption {
+	return func(o *engineOptions) {
+		if o.head.Headers == nil {
+			o.head.Headers = make(http.Header)
+		}
+		o.head.Headers.Add(key, value)
+	}
+}
+
+// WithIndent indents the output of the JSON, JSONP, TOML, XML and YAML engines.
+func WithIndent() EngineOption {
+	return func(o *engineOptions) {
+		o.indent = true
+	}
+}
+
+// WithLayout renders the templates of the HTML engine through the layout template.
+func WithLayout(layout string) EngineOption {
+	return func(o *engineOptions) {
+		o.layout = layout
+	}
+}
+
+// WithPrefix writes prefix before the output of the JSON, MsgPack, TOML, XML and YAML engines.
+func WithPrefix(prefix []byte) EngineOption {
+	return func(o *engineOptions) {
+		o.prefix = prefix
+	}
+}
+
+// WithStreaming makes the JSON, MsgPack and XML engines encode straight to the writer.
+func WithStreaming() EngineOption {
+	return func(o *engineOptions) {
+		o.streaming = true
+	}
+}
+
+// WithUnEscapeHTML keeps HTML characters unescaped in the output of the JSON engine.
+func WithUnEscapeHTML() EngineOption {
+	return func(o *engineOptions) {
+		o.unEscapeHTML = true
+	}
+}
+
+func newEngineOptions(opts []EngineOption) engineOptions {
+	o := engineOptions{head: Head{Status: http.StatusOK}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// NewData returns a Data engine configured with opts, sending binary data
+// unless told otherwise.
+func NewData(opts ...EngineOption) Data {
+	o := newEngineOptions(opts)
+	if o.head.ContentType == "" {
+		o.head.ContentType = ContentBinary
+	}
+	return Data{Head: o.head}
+}
+
+// NewHTML returns a HTML engine rendering the template name of templates,
+// configured with opts. The engine is validated, which also clones templates
+// once for its renders, see HTML.Validate.
+func NewHTML(templates *template.Template, name string, opts ...EngineOption) (HTML, error) {
+	o := newEngineOptions(opts)
+	h := HTML{
+		Head:      o.head,
+		Name:      name,
+		Templates: templates,
+		Layout:    o.layout,
+	}
+	if err := h.Validate(); err != nil {
+		return HTML{}, err
+	}
+	return h, nil
+}
+
+// NewJSON returns a JSON engine configured with opts.
+func NewJSON(opts ...EngineOption) JSON {
+	o := newEngineOptions(opts)
+	return JSON{
+		Head:          o.head,
+		Indent:        o.indent,
+		Prefix:        o.prefix,
+		StreamingJSON: o.streaming,
+		UnEscapeHTML:  o.unEscapeHTML,
+	}
+}
+
+// NewJSONP returns a JSONP engine invoking callback, configured with opts.
+func NewJSONP(callback string, opts ...EngineOption) JSONP {
+	o := newEngineOptions(opts)
+	return JSONP{
+		Head:     o.head,
+		Indent:   o.indent,
+		Callback: callback,
+	}
+}
+
+// NewMsgPack returns a MsgPack engine configured with opts.
+func NewMsgPack(opts ...EngineOption) MsgPack {
+	o := newEngineOptions(opts)
+	return MsgPack{
+		Head:      o.head,
+		Prefix:    o.prefix,
+		Streaming: o.streaming,
+	}
+}
+
+// NewText returns a Text engine configured with opts.
+func NewText(opts ...EngineOption) Text {
+	o := newEngineOptions(opts)
+	return Text{Head: o.head}
+}
+
+// NewTOML returns a TOML engine configured with opts.
+func NewTOML(opts ...EngineOption) TOML {
+	o := newEngineOptions(opts)
+	return TOML{
+		Head:   o.head,
+		Indent: o.indent,
+		Prefix: o.prefix,
+	}
+}
+
+// NewXML returns a XML engine configured with opts.
+func NewXML(opts ...EngineOption) XML {
+	o := newEngineOptions(opts)
+	return XML{
+		Head:         o.head,
+		Indent:       o.indent,
+		Prefix:       o.prefix,
+		StreamingXML: o.streaming,
+	}
+}
+
+// NewYAML returns a YAML engine configured with opts.
+func NewYAML(opts ...EngineOption) YAML {
+	o := newEngineOptions(opts)
+	return YAML{
+		Head:   o.head,
+		Indent: o.indent,
+		Prefix: o.prefix,
+	}
+}
@@ -0,0 +1,38 @@
+package render
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTML(t *testing.T) {
+	tmpl := newTestTemplates(t)
+
+	h, err := NewHTML(tmpl, "page", WithLayout("base"), WithStatus(http.StatusCreated))
+	require.NoError(t, err)
+
+	// The engine renders its own clone of the templates.
+	err = tmpl.ExecuteTemplate(ioutil.Discard, "page", nil)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	err = h.Render(recorder, "page")
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, "text/html; charset=UTF-8", recorder.Header().Get(ContentType))
+	assert.Equal(t, "<main><p>page</p></main>", recorder.Body.String())
+}
+
+func TestNewHTML_unknownTemplate(t *testing.T) {
+	_, err := NewHTML(newTestTemplates(t), "missing")
+	assert.Error(t, err)
+
+	_, err = NewHTML(newTestTemplates(t), "page", WithLayout("missing"))
+	assert.Error(t, err)
+}
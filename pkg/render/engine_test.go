@@ -0,0 +1,951 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type upperList []string
+
+func (l upperList) MarshalJSON() ([]byte, error) {
+	return []byte(`"LIST"`), nil
+}
+
+func TestJSON_RenderCtx_streamingMarshalers(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		value    interface{}
+		expected string
+	}{
+		{
+			desc:     "byte slice",
+			value:    []byte("hi"),
+			expected: "\"aGk=\"\n",
+		},
+		{
+			desc:     "raw message",
+			value:    json.RawMessage(`{"a":1}`),
+			expected: "{\"a\":1}\n",
+		},
+		{
+			desc:     "slice marshaling itself",
+			value:    upperList{"a", "b"},
+			expected: "\"LIST\"\n",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			buf := &bytes.Buffer{}
+			err := JSON{StreamingJSON: true, FlushEveryN: 1}.RenderCtx(ctx, buf, test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, buf.String())
+		})
+	}
+}
+
+func TestJSON_RenderCtx_streamingElements(t *testing.T) {
+	type item struct {
+		Name string
+		Tags []string
+	}
+
+	testCases := []struct {
+		desc   string
+		engine JSON
+		value  interface{}
+	}{
+		{
+			desc:   "ints",
+			engine: JSON{StreamingJSON: true},
+			value:  []int{1, 2, 3},
+		},
+		{
+			desc:   "empty slice",
+			engine: JSON{StreamingJSON: true},
+			value:  []int{},
+		},
+		{
+			desc:   "structs",
+			engine: JSON{StreamingJSON: true},
+			value:  []item{{Name: "a", Tags: []string{"x", "y"}}, {Name: "b"}},
+		},
+		{
+			desc:   "indented structs",
+			engine: JSON{StreamingJSON: true, Indent: true},
+			value:  []item{{Name: "a", Tags: []string{"x", "y"}}, {Name: "b"}},
+		},
+		{
+			desc:   "indented structs with prefix",
+			engine: JSON{StreamingJSON: true, Indent: true, IndentPrefix: "> ", IndentValue: "\t"},
+			value:  [2]item{{Name: "a", Tags: []string{"x"}}, {Name: "b"}},
+		},
+		{
+			desc:   "indented empty slice",
+			engine: JSON{StreamingJSON: true, Indent: true},
+			value:  []item{},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			expected := &bytes.Buffer{}
+			err := test.engine.RenderCtx(context.Background(), expected, test.value)
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			engine := test.engine
+			engine.FlushEveryN = 1
+			buf := &bytes.Buffer{}
+			err = engine.RenderCtx(ctx, buf, test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected.String(), buf.String())
+		})
+	}
+}
+
+func newTestTemplates(t *testing.T) *template.Template {
+	t.Helper()
+
+	tmpl, err := template.New("").Funcs(helperFuncs).Parse(`{{ define "base" }}<main>{{ yield }}</main>{{ end }}{{ define "page" }}<p>{{ . }}</p>{{ end }}`)
+	require.NoError(t, err)
+	return tmpl
+}
+
+func TestHTML_Render_blockThenLayout(t *testing.T) {
+	tmpl := newTestTemplates(t)
+
+	buf := &bytes.Buffer{}
+	err := HTML{Templates: tmpl, Block: "page"}.Render(buf, "fragment")
+	require.NoError(t, err)
+	assert.Equal(t, "<p>fragment</p>", buf.String())
+
+	buf.Reset()
+	err = HTML{Templates: tmpl, Name: "page", Layout: "base"}.Render(buf, "full")
+	require.NoError(t, err)
+	assert.Equal(t, "<main><p>full</p></main>", buf.String())
+}
+
+func TestHTML_Validate_templatesExecutedElsewhere(t *testing.T) {
+	tmpl := newTestTemplates(t)
+
+	page := HTML{Templates: tmpl, Name: "page"}
+	require.NoError(t, page.Validate())
+
+	layout := HTML{Templates: tmpl, Name: "page", Layout: "base"}
+	require.NoError(t, layout.Validate())
+
+	// Executing the templates prevents cloning them from now on.
+	err := tmpl.ExecuteTemplate(ioutil.Discard, "page", nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		buf := &bytes.Buffer{}
+		err = page.Render(buf, "page")
+		require.NoError(t, err)
+		assert.Equal(t, "<p>page</p>", buf.String())
+
+		buf.Reset()
+		err = layout.Render(buf, "full")
+		require.NoError(t, err)
+		assert.Equal(t, "<main><p>full</p></main>", buf.String())
+	}
+}
+
+func TestAddNonce(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		policy   string
+		expected string
+	}{
+		{
+			desc:     "script-src",
+			policy:   "script-src 'self'",
+			expected: "script-src 'self' 'nonce-abc'",
+		},
+		{
+			desc:     "script-src among other directives",
+			policy:   "default-src 'self'; script-src 'self' cdn.example.com; img-src *",
+			expected: "default-src 'self'; script-src 'self' cdn.example.com 'nonce-abc'; img-src *",
+		},
+		{
+			desc:     "script-src none",
+			policy:   "script-src 'none'",
+			expected: "script-src 'nonce-abc'",
+		},
+		{
+			desc:     "default-src only",
+			policy:   "default-src 'self'; img-src *",
+			expected: "default-src 'self'; img-src *; script-src 'self' 'nonce-abc'",
+		},
+		{
+			desc:     "no script restriction",
+			policy:   "img-src *",
+			expected: "img-src *",
+		},
+		{
+			desc:     "several policies",
+			policy:   "script-src 'self', default-src https:",
+			expected: "script-src 'self' 'nonce-abc', default-src https:; script-src https: 'nonce-abc'",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, addNonce(test.policy, "abc"))
+		})
+	}
+}
+
+func TestHTML_Render_nonceWithPolicy(t *testing.T) {
+	tmpl := newTestTemplates(t)
+
+	recorder := httptest.NewRecorder()
+	h := HTML{
+		Head:      Head{Status: http.StatusOK, Headers: http.Header{ContentSecurityPolicy: {"script-src 'self'"}}},
+		Templates: tmpl,
+		Name:      "page",
+		Nonce:     true,
+	}
+	err := h.Render(recorder, "page")
+	require.NoError(t, err)
+
+	policies := recorder.Header()[ContentSecurityPolicy]
+	require.Len(t, policies, 1)
+	assert.Regexp(t, `^script-src 'self' 'nonce-[^']+'$`, policies[0])
+	assert.Equal(t, []string{"script-src 'self'"}, h.Head.Headers[ContentSecurityPolicy])
+}
+
+func TestJSON_RenderCtx_channelConversion(t *testing.T) {
+	type item struct {
+		Name  string            `api:"name"`
+		Count int               `api:"count"`
+		Tags  map[string]string `api:"tags"`
+	}
+	items := []item{
+		{Name: "a", Count: 1, Tags: map[string]string{"z": "1", "b": "2"}},
+		{Name: "b", Count: 2},
+	}
+
+	testCases := []struct {
+		desc   string
+		engine JSON
+	}{
+		{
+			desc:   "tag name",
+			engine: JSON{StreamingJSON: true, TagName: "api"},
+		},
+		{
+			desc:   "fields",
+			engine: JSON{StreamingJSON: true, TagName: "api", Fields: []string{"name"}},
+		},
+		{
+			desc:   "sorted keys",
+			engine: JSON{StreamingJSON: true, TagName: "api", SortKeys: true},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			expected := &bytes.Buffer{}
+			err := test.engine.Render(expected, items)
+			require.NoError(t, err)
+
+			ch := make(chan item, len(items))
+			for _, i := range items {
+				ch <- i
+			}
+			close(ch)
+
+			buf := &bytes.Buffer{}
+			err = test.engine.Render(buf, ch)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected.String(), buf.String())
+		})
+	}
+}
+
+func TestJSON_RenderCtx_channelEnvelope(t *testing.T) {
+	ch := make(chan int)
+	close(ch)
+
+	err := JSON{StreamingJSON: true, Envelope: "data"}.Render(&bytes.Buffer{}, ch)
+	assert.Error(t, err)
+}
+
+func TestUnescapeHTML(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		src      string
+		expected string
+	}{
+		{
+			desc:     "escaped HTML characters",
+			src:      `"\u003cb\u003e \u0026"`,
+			expected: `"<b> &"`,
+		},
+		{
+			desc:     "adjacent escapes",
+			src:      `"\u003c\u003e\u0026\u003c"`,
+			expected: `"<>&<"`,
+		},
+		{
+			desc:     "escaped backslash followed by u003c",
+			src:      `"\\u003c"`,
+			expected: `"\\u003c"`,
+		},
+		{
+			desc:     "escaped backslash followed by an escape",
+			src:      `"\\\u003c"`,
+			expected: `"\\<"`,
+		},
+		{
+			desc:     "other escapes",
+			src:      `"\u0022\n "`,
+			expected: `"\u0022\n "`,
+		},
+		{
+			desc:     "truncated escape",
+			src:      `"\u003"`,
+			expected: `"\u003"`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			unescapeHTML(buf, []byte(test.src))
+
+			assert.Equal(t, test.expected, buf.String())
+		})
+	}
+}
+
+func TestJSON_Render_unEscapeHTMLLiterals(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := JSON{UnEscapeHTML: true}.Render(buf, map[string]string{"a": `<b> \u0026`})
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"a":"<b> \\u0026"}`, buf.String())
+}
+
+func TestJSONP_Render_contentType(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		contentType string
+		expected    string
+	}{
+		{
+			desc:     "default",
+			expected: "application/javascript; charset=UTF-8",
+		},
+		{
+			desc:        "override",
+			contentType: "text/javascript",
+			expected:    "text/javascript",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			j := JSONP{
+				Head:     Head{ContentType: test.contentType, Status: http.StatusOK},
+				Callback: "cb",
+			}
+			err := j.Render(recorder, map[string]int{"a": 1})
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, recorder.Header().Get(ContentType))
+			assert.Equal(t, `cb({"a":1});`, recorder.Body.String())
+		})
+	}
+}
+
+type failingMarshaler struct{}
+
+func (failingMarshaler) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("marshal failure")
+}
+
+func (failingMarshaler) MarshalXML(*xml.Encoder, xml.StartElement) error {
+	return errors.New("marshal failure")
+}
+
+func TestRender_marshalError(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		engine Engine
+		value  interface{}
+	}{
+		{
+			desc:   "JSON channel",
+			engine: JSON{Head: Head{Status: http.StatusOK}},
+			value:  make(chan int),
+		},
+		{
+			desc:   "JSON failing marshaler",
+			engine: JSON{Head: Head{Status: http.StatusOK}, AppendNewline: NewlineAlways},
+			value:  failingMarshaler{},
+		},
+		{
+			desc:   "indented JSON failing marshaler",
+			engine: JSON{Head: Head{Status: http.StatusOK}, Indent: true},
+			value:  failingMarshaler{},
+		},
+		{
+			desc:   "XML channel",
+			engine: XML{Head: Head{Status: http.StatusOK}},
+			value:  make(chan int),
+		},
+		{
+			desc:   "indented XML failing marshaler",
+			engine: XML{Head: Head{Status: http.StatusOK}, Indent: true, XMLHeader: true},
+			value:  failingMarshaler{},
+		},
+		{
+			desc:   "YAML func field",
+			engine: YAML{Head: Head{Status: http.StatusOK}},
+			value:  struct{ F func() }{F: func() {}},
+		},
+		{
+			desc:   "YAML channel",
+			engine: YAML{Head: Head{Status: http.StatusOK}},
+			value:  map[string]interface{}{"c": make(chan int)},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			err := test.engine.Render(recorder, test.value)
+			require.Error(t, err)
+
+			assert.Empty(t, recorder.Body.String())
+			assert.Empty(t, recorder.Header())
+			assert.False(t, recorder.Flushed)
+		})
+	}
+}
+
+type benchmarkItem struct {
+	ID    int      `json:"id" xml:"id"`
+	Name  string   `json:"name" xml:"name"`
+	Tags  []string `json:"tags" xml:"tag"`
+	Ready bool     `json:"ready" xml:"ready"`
+}
+
+type benchmarkItems struct {
+	Items []benchmarkItem `xml:"item"`
+}
+
+func newBenchmarkItems() benchmarkItems {
+	items := make([]benchmarkItem, 100)
+	for i := range items {
+		items[i] = benchmarkItem{ID: i, Name: "router@file", Tags: []string{"web", "api"}, Ready: i%2 == 0}
+	}
+	return benchmarkItems{Items: items}
+}
+
+// benchmarkRender benchmarks rendering items with the engine against marshaling
+// them into a fresh slice, as the engines did before pooling their buffers.
+func benchmarkRender(b *testing.B, engine Engine, marshal func(interface{}) ([]byte, error)) {
+	items := newBenchmarkItems()
+	recorder := httptest.NewRecorder()
+
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			recorder.Body.Reset()
+			if err := engine.Render(recorder, items); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("marshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			recorder.Body.Reset()
+			result, err := marshal(items)
+			if err != nil {
+				b.Fatal(err)
+			}
+			recorder.Write(result)
+		}
+	})
+}
+
+func BenchmarkJSONRender(b *testing.B) {
+	benchmarkRender(b, JSON{Head: Head{Status: http.StatusOK}, Indent: true}, func(v interface{}) ([]byte, error) {
+		return json.MarshalIndent(v, "", "  ")
+	})
+}
+
+func BenchmarkXMLRender(b *testing.B) {
+	benchmarkRender(b, XML{Head: Head{Status: http.StatusOK}, Indent: true}, func(v interface{}) ([]byte, error) {
+		return xml.MarshalIndent(v, "", "  ")
+	})
+}
+
+func BenchmarkTextRender(b *testing.B) {
+	text := strings.Repeat("traefik ", 128*1024)
+
+	writers := []struct {
+		desc string
+		w    io.Writer
+	}{
+		{desc: "string writer", w: ioutil.Discard},
+		{desc: "writer", w: struct{ io.Writer }{ioutil.Discard}},
+	}
+
+	for _, writer := range writers {
+		writer := writer
+		b.Run(writer.desc, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(text)))
+			for i := 0; i < b.N; i++ {
+				if err := (Text{}).Render(writer.w, text); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestMsgPack_Render(t *testing.T) {
+	type tagged struct {
+		A int    `msg:"a"`
+		B string `msg:",omitempty"`
+		C int    `msg:"-"`
+	}
+
+	testCases := []struct {
+		desc     string
+		engine   MsgPack
+		value    interface{}
+		expected []byte
+	}{
+		{
+			desc:     "map",
+			value:    map[string]interface{}{"a": 1},
+			expected: []byte{0x81, 0xa1, 'a', 0x01},
+		},
+		{
+			desc:     "slice",
+			value:    []string{"a"},
+			expected: []byte{0x91, 0xa1, 'a'},
+		},
+		{
+			desc:     "plain struct",
+			value:    struct{ A int }{A: 1},
+			expected: []byte{0x81, 0xa1, 'A', 0x01},
+		},
+		{
+			desc:     "tagged struct",
+			value:    &tagged{A: 1, C: 2},
+			expected: []byte{0x81, 0xa1, 'a', 0x01},
+		},
+		{
+			desc:     "map with integer keys",
+			value:    map[int]string{2: "b", 1: "a"},
+			expected: []byte{0x82, 0x01, 0xa1, 'a', 0x02, 0xa1, 'b'},
+		},
+		{
+			desc:     "struct in an interface slice",
+			value:    []interface{}{nil, struct{ A int }{A: 1}},
+			expected: []byte{0x92, 0xc0, 0x81, 0xa1, 'A', 0x01},
+		},
+		{
+			desc:     "streaming plain struct",
+			engine:   MsgPack{Streaming: true},
+			value:    struct{ A int }{A: 1},
+			expected: []byte{0x81, 0xa1, 'A', 0x01},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			engine := test.engine
+			engine.Head.Status = http.StatusOK
+			recorder := httptest.NewRecorder()
+			err := engine.Render(recorder, test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, recorder.Body.Bytes())
+		})
+	}
+}
+
+func TestMsgPack_Render_error(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		value interface{}
+	}{
+		{
+			desc:  "channel",
+			value: make(chan int),
+		},
+		{
+			desc:  "func field",
+			value: struct{ F func() }{F: func() {}},
+		},
+		{
+			desc:  "channel in a map",
+			value: map[string]interface{}{"a": struct{ C chan int }{}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			err := MsgPack{Head: Head{Status: http.StatusOK}}.Render(recorder, test.value)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "render: MsgPack cannot encode")
+
+			assert.Empty(t, recorder.Header())
+			assert.Empty(t, recorder.Body.Bytes())
+		})
+	}
+}
+
+func TestAddVary(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		header   http.Header
+		values   []string
+		expected []string
+	}{
+		{
+			desc:     "no Vary header",
+			header:   http.Header{},
+			values:   []string{"Accept"},
+			expected: []string{"Accept"},
+		},
+		{
+			desc:     "other value",
+			header:   http.Header{Vary: {"Origin"}},
+			values:   []string{"Accept"},
+			expected: []string{"Origin", "Accept"},
+		},
+		{
+			desc:     "value already listed with another case",
+			header:   http.Header{Vary: {"Origin, accept"}},
+			values:   []string{"Accept"},
+			expected: []string{"Origin, accept"},
+		},
+		{
+			desc:     "wildcard",
+			header:   http.Header{Vary: {"*"}},
+			values:   []string{"Accept"},
+			expected: []string{"*"},
+		},
+		{
+			desc:     "repeated values",
+			header:   http.Header{},
+			values:   []string{"Accept", "Accept-Encoding", "Accept"},
+			expected: []string{"Accept", "Accept-Encoding"},
+		},
+		{
+			desc:   "no values",
+			header: http.Header{},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			addVary(test.header, test.values...)
+			assert.Equal(t, test.expected, test.header[Vary])
+		})
+	}
+}
+
+func TestHead_Write_vary(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	recorder.Header().Set(Vary, "Origin")
+
+	head := Head{Status: http.StatusOK, Vary: []string{"Accept", "origin"}}
+	head.Write(recorder)
+
+	assert.Equal(t, []string{"Origin", "Accept"}, recorder.Header()[Vary])
+	assert.Equal(t, []string{"Accept", "origin"}, head.Vary)
+}
+
+func TestRender_bom(t *testing.T) {
+	type item struct {
+		A int
+	}
+
+	testCases := []struct {
+		desc     string
+		engine   Engine
+		value    interface{}
+		expected string
+	}{
+		{
+			desc:     "JSON",
+			engine:   JSON{Head: Head{Status: http.StatusOK}, BOM: true},
+			value:    []int{1},
+			expected: utf8BOM + "[1]",
+		},
+		{
+			desc:     "JSON with a prefix",
+			engine:   JSON{Head: Head{Status: http.StatusOK}, BOM: true, Prefix: []byte(")]}',\n")},
+			value:    []int{1},
+			expected: utf8BOM + ")]}',\n[1]",
+		},
+		{
+			desc:     "streaming JSON",
+			engine:   JSON{Head: Head{Status: http.StatusOK}, BOM: true, StreamingJSON: true},
+			value:    []int{1},
+			expected: utf8BOM + "[1]\n",
+		},
+		{
+			desc:     "XML with a header",
+			engine:   XML{Head: Head{Status: http.StatusOK}, BOM: true, XMLHeader: true},
+			value:    item{A: 1},
+			expected: utf8BOM + xml.Header + "<item><A>1</A></item>",
+		},
+		{
+			desc:     "streaming XML",
+			engine:   XML{Head: Head{Status: http.StatusOK}, BOM: true, StreamingXML: true},
+			value:    item{A: 1},
+			expected: utf8BOM + "<item><A>1</A></item>",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			err := test.engine.Render(recorder, test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, recorder.Body.String())
+			if length := recorder.Header().Get(ContentLength); length != "" {
+				assert.Equal(t, strconv.Itoa(len(test.expected)), length)
+			}
+		})
+	}
+}
+
+func TestRender_bomMarshalError(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		engine Engine
+	}{
+		{
+			desc:   "JSON",
+			engine: JSON{Head: Head{Status: http.StatusOK}, BOM: true},
+		},
+		{
+			desc:   "XML",
+			engine: XML{Head: Head{Status: http.StatusOK}, BOM: true},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			err := test.engine.Render(recorder, make(chan int))
+			require.Error(t, err)
+
+			assert.Empty(t, recorder.Body.String())
+		})
+	}
+}
+
+func newLangTemplates(t *testing.T) *template.Template {
+	t.Helper()
+
+	tmpl, err := template.New("").Funcs(helperFuncs).Parse(`{{ define "base" }}<main>{{ yield }}</main>{{ end }}` +
+		`{{ define "base.fr" }}<main lang="fr">{{ yield }}</main>{{ end }}` +
+		`{{ define "page" }}<p>{{ T "hello" . }}</p>{{ end }}` +
+		`{{ define "page.fr" }}<p>fr: {{ T "hello" . }}</p>{{ end }}` +
+		`{{ define "page.fr-CA" }}<p>fr-CA: {{ T "hello" . }}</p>{{ end }}`)
+	require.NoError(t, err)
+	return tmpl
+}
+
+func translate(lang, key string, args ...interface{}) string {
+	return lang + ":" + key + fmt.Sprint(args...)
+}
+
+func TestHTML_Render_lang(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		lang     string
+		layout   string
+		expected string
+	}{
+		{
+			desc:     "exact language",
+			lang:     "fr-CA",
+			expected: "<p>fr-CA: fr-CA:hello!</p>",
+		},
+		{
+			desc:     "base language",
+			lang:     "fr-FR",
+			expected: "<p>fr: fr-FR:hello!</p>",
+		},
+		{
+			desc:     "base language with underscore",
+			lang:     "fr_BE",
+			expected: "<p>fr: fr_BE:hello!</p>",
+		},
+		{
+			desc:     "fallback to the base name",
+			lang:     "de",
+			expected: "<p>de:hello!</p>",
+		},
+		{
+			desc:     "localized layout",
+			lang:     "fr",
+			layout:   "base",
+			expected: `<main lang="fr"><p>fr: fr:hello!</p></main>`,
+		},
+		{
+			desc:     "fallback layout",
+			lang:     "de",
+			layout:   "base",
+			expected: "<main><p>de:hello!</p></main>",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			h := HTML{
+				Templates: newLangTemplates(t),
+				Name:      "page",
+				Layout:    test.layout,
+				Lang:      test.lang,
+				Translate: translate,
+			}
+			buf := &bytes.Buffer{}
+			err := h.Render(buf, "!")
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, buf.String())
+		})
+	}
+}
+
+func TestHTML_Render_langValidated(t *testing.T) {
+	h := HTML{
+		Templates: newLangTemplates(t),
+		Name:      "page",
+		Lang:      "fr",
+		Translate: translate,
+	}
+	require.NoError(t, h.Validate())
+
+	for i := 0; i < 2; i++ {
+		buf := &bytes.Buffer{}
+		err := h.Render(buf, "!")
+		require.NoError(t, err)
+
+		assert.Equal(t, "<p>fr: fr:hello!</p>", buf.String())
+	}
+}
+
+func TestHTML_Render_langError(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		engine      HTML
+		noTemplates bool
+	}{
+		{
+			desc:   "no Lang",
+			engine: HTML{Name: "page", Translate: translate},
+		},
+		{
+			desc:   "no Translate",
+			engine: HTML{Name: "page", Lang: "de"},
+		},
+		{
+			desc:        "nil templates",
+			engine:      HTML{Name: "page", Lang: "fr", Translate: translate},
+			noTemplates: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			h := test.engine
+			if !test.noTemplates {
+				h.Templates = newLangTemplates(t)
+			}
+			h.Head.Status = http.StatusOK
+			recorder := httptest.NewRecorder()
+			err := h.Render(recorder, "!")
+			require.Error(t, err)
+
+			assert.Empty(t, recorder.Header())
+			assert.Empty(t, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package render
+
+import (
+	"net/http"
+)
+
+// Error is an error response flowing through the same engines as successful
+// responses. When an engine supporting it is given an *Error, its Payload is
+// rendered in place of the error with the error's Status.
+//
+// The CBOR, HTML, JSON, JSONP, MsgPack, Text, TOML, XML and YAML engines support it.
+type Error struct {
+	// Status overrides Head.Status when not zero.
+	Status  int
+	Payload interface{}
+	// Err is the underlying error, if any.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return http.StatusText(e.Status)
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// errorPayload returns the payload of v if it's an *Error, setting the status
+// from it, or v itself otherwise.
+func (h *Head) errorPayload(v interface{}) interface{} {
+	e, ok := v.(*Error)
+	if !ok || e == nil {
+		return v
+	}
+	if e.Status != 0 {
+		h.Status = e.Status
+	}
+	return e.Payload
+}
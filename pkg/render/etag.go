@@ -0,0 +1,76 @@
+package render
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ETag is a wrapper engine setting an ETag header computed from the body
+// rendered by another engine.
+//
+// Since engines never see the request, the caller passes the request's
+// If-None-Match header value. When it matches, a 304 Not Modified is written
+// without a body. Writers which aren't an http.ResponseWriter get the inner
+// engine's output untouched.
+type ETag struct {
+	Engine      Engine
+	IfNoneMatch string
+	Weak        bool
+}
+
+// Render a response with an ETag header.
+func (e ETag) Render(w io.Writer, v interface{}) error {
+	hw, ok := w.(http.ResponseWriter)
+	if !ok {
+		return e.Engine.Render(w, v)
+	}
+
+	rec := newBufferedResponseWriter()
+	if err := e.Engine.Render(rec, v); err != nil {
+		return err
+	}
+
+	rec.copyHeader(hw)
+	if rec.status == http.StatusOK {
+		sum := sha256.Sum256(rec.body.Bytes())
+		tag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		if e.Weak {
+			tag = "W/" + tag
+		}
+		hw.Header().Set("ETag", tag)
+
+		if etagMatch(e.IfNoneMatch, tag) {
+			hw.Header().Del(ContentLength)
+			hw.Header().Del(ContentType)
+			hw.WriteHeader(http.StatusNotModified)
+			return nil
+		}
+	}
+
+	hw.Header().Set(ContentLength, strconv.Itoa(rec.body.Len()))
+	if rec.status != 0 {
+		hw.WriteHeader(rec.status)
+	}
+	_, err := rec.body.WriteTo(hw)
+	return err
+}
+
+// etagMatch reports whether the If-None-Match header value matches tag, using
+// the weak comparison function required for If-None-Match.
+func etagMatch(ifNoneMatch, tag string) bool {
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+
+	tag = strings.TrimPrefix(tag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
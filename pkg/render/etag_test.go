@@ -0,0 +1,96 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// helloETag is the ETag of the "hello" body.
+const helloETag = `"2cf24dba5fb0a30e26e83b2ac5b9e29e"`
+
+func TestETag_Render(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		engine         ETag
+		expectedStatus int
+		expectedETag   string
+		expectedBody   string
+	}{
+		{
+			desc:           "strong",
+			engine:         ETag{Engine: Text{Head: Head{Status: http.StatusOK}}},
+			expectedStatus: http.StatusOK,
+			expectedETag:   helloETag,
+			expectedBody:   "hello",
+		},
+		{
+			desc:           "weak",
+			engine:         ETag{Engine: Text{Head: Head{Status: http.StatusOK}}, Weak: true},
+			expectedStatus: http.StatusOK,
+			expectedETag:   "W/" + helloETag,
+			expectedBody:   "hello",
+		},
+		{
+			desc:           "matching If-None-Match",
+			engine:         ETag{Engine: Text{Head: Head{Status: http.StatusOK}}, IfNoneMatch: `"other", W/` + helloETag},
+			expectedStatus: http.StatusNotModified,
+			expectedETag:   helloETag,
+		},
+		{
+			desc:           "wildcard If-None-Match",
+			engine:         ETag{Engine: Text{Head: Head{Status: http.StatusOK}}, IfNoneMatch: "*"},
+			expectedStatus: http.StatusNotModified,
+			expectedETag:   helloETag,
+		},
+		{
+			desc:           "other If-None-Match",
+			engine:         ETag{Engine: Text{Head: Head{Status: http.StatusOK}}, IfNoneMatch: `"other"`},
+			expectedStatus: http.StatusOK,
+			expectedETag:   helloETag,
+			expectedBody:   "hello",
+		},
+		{
+			desc:           "not a 200",
+			engine:         ETag{Engine: Text{Head: Head{Status: http.StatusNotFound}}, IfNoneMatch: "*"},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "hello",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			err := test.engine.Render(recorder, "hello")
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedETag, recorder.Header().Get("ETag"))
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestETag_Render_noStatus(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	err := ETag{Engine: emptyEngine{}}.Render(recorder, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, recorder.Body.String())
+}
+
+func TestETag_Render_error(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	err := ETag{Engine: JSON{Head: Head{Status: http.StatusOK}}}.Render(recorder, make(chan int))
+	require.Error(t, err)
+
+	assert.Empty(t, recorder.Header())
+	assert.Empty(t, recorder.Body.String())
+}
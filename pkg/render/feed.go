@@ -0,0 +1,253 @@
+package render
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Feed is a syndication feed rendered by the RSS and Atom engines.
+type Feed struct {
+	Title       string
+	Link        string
+	Description string
+	// ID identifies an Atom feed, defaulting to Link.
+	ID      string
+	Author  string
+	Updated time.Time
+	Items   []FeedItem
+}
+
+// FeedItem is a single item, or entry, of a Feed.
+type FeedItem struct {
+	Title       string
+	Link        string
+	Description string
+	// ID is the RSS guid or Atom id of the item, defaulting to Link.
+	ID        string
+	Author    string
+	Published time.Time
+	Updated   time.Time
+}
+
+// RSS built-in renderer for RSS 2.0 feeds.
+type RSS struct {
+	Head
+	Indent bool
+}
+
+// Atom built-in renderer for Atom feeds.
+type Atom struct {
+	Head
+	Indent bool
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title,omitempty"`
+	Link        string   `xml:"link,omitempty"`
+	Description string   `xml:"description,omitempty"`
+	Author      string   `xml:"author,omitempty"`
+	GUID        *rssGUID `xml:"guid,omitempty"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink string `xml:"isPermaLink,attr,omitempty"`
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Link     *atomLink   `xml:"link,omitempty"`
+	Author   *atomPerson `xml:"author,omitempty"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Updated   string      `xml:"updated"`
+	Published string      `xml:"published,omitempty"`
+	Link      *atomLink   `xml:"link,omitempty"`
+	Author    *atomPerson `xml:"author,omitempty"`
+	Summary   string      `xml:"summary,omitempty"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+// Render a RSS feed response.
+func (r RSS) Render(w io.Writer, v interface{}) error {
+	feed, err := feedOf("RSS", v)
+	if err != nil {
+		return err
+	}
+
+	doc := rssDocument{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         feed.Title,
+			Link:          feed.Link,
+			Description:   feed.Description,
+			LastBuildDate: rssDate(feed.Updated),
+		},
+	}
+	for _, item := range feed.Items {
+		ri := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Description,
+			Author:      item.Author,
+			PubDate:     rssDate(item.Published),
+		}
+		if item.ID != "" {
+			ri.GUID = &rssGUID{Value: item.ID}
+			// Only a guid which is the item's link can be followed.
+			if item.ID != item.Link {
+				ri.GUID.IsPermaLink = "false"
+			}
+		} else if item.Link != "" {
+			ri.GUID = &rssGUID{Value: item.Link}
+		}
+		doc.Channel.Items = append(doc.Channel.Items, ri)
+	}
+
+	r.Head.setDefaultContentType(ContentRSS)
+
+	x := XML{
+		Head:      r.Head,
+		Indent:    r.Indent,
+		XMLHeader: true,
+	}
+
+	return x.Render(w, doc)
+}
+
+// Render an Atom feed response.
+func (a Atom) Render(w io.Writer, v interface{}) error {
+	feed, err := feedOf("Atom", v)
+	if err != nil {
+		return err
+	}
+
+	doc := atomFeed{
+		Title:    feed.Title,
+		Subtitle: feed.Description,
+		ID:       feed.ID,
+	}
+	if doc.ID == "" {
+		doc.ID = feed.Link
+	}
+	if feed.Link != "" {
+		doc.Link = &atomLink{Href: feed.Link}
+	}
+	if feed.Author != "" {
+		doc.Author = &atomPerson{Name: feed.Author}
+	}
+
+	// A feed is last updated when its most recent entry was, unless told otherwise.
+	updated := feed.Updated
+	for _, item := range feed.Items {
+		entry := atomEntry{
+			Title:     item.Title,
+			ID:        item.ID,
+			Published: atomDate(item.Published),
+			Summary:   item.Description,
+		}
+		if entry.ID == "" {
+			entry.ID = item.Link
+		}
+		if item.Link != "" {
+			entry.Link = &atomLink{Href: item.Link}
+		}
+		if item.Author != "" {
+			entry.Author = &atomPerson{Name: item.Author}
+		}
+
+		itemUpdated := item.Updated
+		if itemUpdated.IsZero() {
+			itemUpdated = item.Published
+		}
+		entry.Updated = atomDate(itemUpdated)
+		if feed.Updated.IsZero() && itemUpdated.After(updated) {
+			updated = itemUpdated
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	// Atom requires the feed and its entries to have an updated date, which
+	// defaults to the time of the render without any date to go by.
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	doc.Updated = atomDate(updated)
+	for i := range doc.Entries {
+		if doc.Entries[i].Updated == "" {
+			doc.Entries[i].Updated = doc.Updated
+		}
+	}
+
+	a.Head.setDefaultContentType(ContentAtom)
+
+	x := XML{
+		Head:      a.Head,
+		Indent:    a.Indent,
+		XMLHeader: true,
+	}
+
+	return x.Render(w, doc)
+}
+
+// feedOf returns the Feed held by v.
+func feedOf(engine string, v interface{}) (Feed, error) {
+	switch f := v.(type) {
+	case Feed:
+		return f, nil
+	case *Feed:
+		if f == nil {
+			return Feed{}, fmt.Errorf("render: %s cannot render a nil *Feed", engine)
+		}
+		return *f, nil
+	}
+	return Feed{}, fmt.Errorf("render: %s expects Feed, got %T", engine, v)
+}
+
+// rssDate formats t as required by RSS, an empty string for the zero time.
+func rssDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC1123Z)
+}
+
+// atomDate formats t as required by Atom, an empty string for the zero time.
+func atomDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,78 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAtom_Render_updated(t *testing.T) {
+	published := time.Date(2019, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc     string
+		feed     Feed
+		expected string
+	}{
+		{
+			desc: "newest entry",
+			feed: Feed{Title: "feed", Items: []FeedItem{
+				{Title: "a", Published: published},
+				{Title: "b", Published: published.Add(time.Hour)},
+			}},
+			expected: "2019-03-01T13:00:00Z",
+		},
+		{
+			desc: "feed",
+			feed: Feed{Title: "feed", Updated: published, Items: []FeedItem{
+				{Title: "a", Published: published.Add(time.Hour)},
+			}},
+			expected: "2019-03-01T12:00:00Z",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			err := Atom{}.Render(buf, test.feed)
+			require.NoError(t, err)
+
+			var doc atomFeed
+			require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+			assert.Equal(t, test.expected, doc.Updated)
+		})
+	}
+}
+
+func TestAtom_Render_noDates(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := Atom{}.Render(buf, Feed{Title: "feed", Items: []FeedItem{{Title: "a"}}})
+	require.NoError(t, err)
+
+	var doc atomFeed
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+
+	updated, err := time.Parse(time.RFC3339, doc.Updated)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), updated, time.Minute)
+
+	require.Len(t, doc.Entries, 1)
+	assert.Equal(t, doc.Updated, doc.Entries[0].Updated)
+}
+
+func TestFeed_Render_nilFeed(t *testing.T) {
+	var feed *Feed
+
+	err := RSS{}.Render(&bytes.Buffer{}, feed)
+	assert.Error(t, err)
+
+	err = Atom{}.Render(&bytes.Buffer{}, feed)
+	assert.Error(t, err)
+}
@@ -0,0 +1,155 @@
+package render
+
+import (
+	"encoding"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Form built-in renderer for application/x-www-form-urlencoded data.
+//
+// The value is a url.Values, a map[string]string, a map[string][]string or a
+// struct, whose field names are read from the `form` struct tag when present.
+// Slice values repeat their key. Struct fields keep their order, set SortKeys
+// for a deterministic order of map keys.
+type Form struct {
+	Head
+	SortKeys bool
+}
+
+// formField is a key of the encoded form along with its values.
+type formField struct {
+	key    string
+	values []string
+}
+
+// Render a form-urlencoded response.
+func (f Form) Render(w io.Writer, v interface{}) error {
+	fields, err := formFields(v)
+	if err != nil {
+		return err
+	}
+	if f.SortKeys {
+		sort.SliceStable(fields, func(i, j int) bool {
+			return fields[i].key < fields[j].key
+		})
+	}
+
+	var b strings.Builder
+	for _, field := range fields {
+		key := url.QueryEscape(field.key)
+		for _, value := range field.values {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(key + "=" + url.QueryEscape(value))
+		}
+	}
+	result := b.String()
+
+	if hw, ok := w.(http.ResponseWriter); ok {
+		if f.Head.ContentType == "" {
+			f.Head.ContentType = ContentForm
+		}
+		hw.Header().Set(ContentLength, strconv.Itoa(len(result)))
+		f.Head.Write(hw)
+	}
+	_, err = io.WriteString(w, result)
+	return err
+}
+
+// formFields flattens v into form fields.
+func formFields(v interface{}) ([]formField, error) {
+	switch m := v.(type) {
+	case url.Values:
+		return formFieldsOf(m), nil
+	case map[string][]string:
+		return formFieldsOf(m), nil
+	case map[string]string:
+		fields := make([]formField, 0, len(m))
+		for k, value := range m {
+			fields = append(fields, formField{key: k, values: []string{value}})
+		}
+		return fields, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("render: Form expects url.Values, a string map or a struct, got %T", v)
+	}
+
+	var fields []formField
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name, opts := f.Name, ""
+		if tag := f.Tag.Get("form"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if idx := strings.Index(tag, ","); idx != -1 {
+				tag, opts = tag[:idx], tag[idx+1:]
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+
+		fv := rv.Field(i)
+		if hasOption(opts, "omitempty") && isEmptyValue(fv) {
+			continue
+		}
+
+		field := formField{key: name}
+		if (fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8) || fv.Kind() == reflect.Array {
+			for j := 0; j < fv.Len(); j++ {
+				field.values = append(field.values, formValue(fv.Index(j)))
+			}
+		} else {
+			field.values = []string{formValue(fv)}
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func formFieldsOf(m map[string][]string) []formField {
+	fields := make([]formField, 0, len(m))
+	for k, values := range m {
+		fields = append(fields, formField{key: k, values: values})
+	}
+	return fields
+}
+
+// formValue formats a single value, using its text marshaling when available.
+func formValue(rv reflect.Value) string {
+	if (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil() {
+		return ""
+	}
+	if tm, ok := rv.Interface().(encoding.TextMarshaler); ok {
+		if b, err := tm.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.Slice {
+		// Byte slices are sent as text.
+		return string(rv.Bytes())
+	}
+	return fmt.Sprint(rv.Interface())
+}
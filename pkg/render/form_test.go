@@ -0,0 +1,113 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForm_Render(t *testing.T) {
+	type login struct {
+		User     string    `form:"user"`
+		Scopes   []string  `form:"scope"`
+		Remember bool      `form:"remember,omitempty"`
+		Password string    `form:"-"`
+		Since    time.Time `form:"since"`
+		Token    []byte
+		Nickname *string `form:"nick"`
+	}
+
+	testCases := []struct {
+		desc     string
+		engine   Form
+		value    interface{}
+		expected string
+	}{
+		{
+			desc:     "url.Values",
+			engine:   Form{SortKeys: true},
+			value:    url.Values{"b": {"2", "3"}, "a": {"x y"}},
+			expected: "a=x+y&b=2&b=3",
+		},
+		{
+			desc:     "string map",
+			engine:   Form{SortKeys: true},
+			value:    map[string]string{"b": "&", "a": "="},
+			expected: "a=%3D&b=%26",
+		},
+		{
+			desc:     "struct",
+			value:    login{User: "jane", Scopes: []string{"read", "write"}, Password: "secret", Since: time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC), Token: []byte("abc")},
+			expected: "user=jane&scope=read&scope=write&since=2019-01-02T03%3A04%3A05Z&Token=abc&nick=",
+		},
+		{
+			desc:     "pointer to a struct",
+			value:    &struct{ A int }{A: 1},
+			expected: "A=1",
+		},
+		{
+			desc:     "nil url.Values",
+			value:    url.Values(nil),
+			expected: "",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			engine := test.engine
+			engine.Head.Status = http.StatusOK
+			recorder := httptest.NewRecorder()
+			err := engine.Render(recorder, test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, recorder.Body.String())
+			assert.Equal(t, ContentForm, recorder.Header().Get(ContentType))
+		})
+	}
+}
+
+func TestForm_Render_invalidValue(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		value interface{}
+	}{
+		{
+			desc:  "nil",
+			value: nil,
+		},
+		{
+			desc:  "nil pointer to a struct",
+			value: (*struct{ A int })(nil),
+		},
+		{
+			desc:  "integer map",
+			value: map[string]int{"a": 1},
+		},
+		{
+			desc:  "slice",
+			value: []string{"a"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			err := Form{Head: Head{Status: http.StatusOK}}.Render(recorder, test.value)
+			require.Error(t, err)
+
+			assert.Empty(t, recorder.Header())
+			assert.Empty(t, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// GeoJSON built-in renderer for RFC 7946 documents. The value must marshal to
+// a Feature, a FeatureCollection or a geometry object carrying its required members.
+type GeoJSON struct {
+	Head
+	Indent bool
+}
+
+// geoJSONObject holds the raw members of a GeoJSON object.
+type geoJSONObject map[string]json.RawMessage
+
+// Render a GeoJSON response.
+func (g GeoJSON) Render(w io.Writer, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var obj geoJSONObject
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return fmt.Errorf("render: GeoJSON expects an object, got %T", v)
+	}
+	if err := obj.validate(); err != nil {
+		return err
+	}
+
+	g.Head.setDefaultContentType(ContentGeoJSON)
+
+	j := JSON{
+		Head:   g.Head,
+		Indent: g.Indent,
+	}
+
+	return j.Render(w, json.RawMessage(b))
+}
+
+// validate checks the type of the object and the presence of the members it requires.
+func (o geoJSONObject) validate() error {
+	var typ string
+	if err := json.Unmarshal(o["type"], &typ); err != nil || typ == "" {
+		return fmt.Errorf("render: GeoJSON object has no type")
+	}
+
+	var member string
+	switch typ {
+	case "FeatureCollection":
+		member = "features"
+	case "Feature":
+		member = "geometry"
+	case "GeometryCollection":
+		member = "geometries"
+	case "Point", "MultiPoint", "LineString", "MultiLineString", "Polygon", "MultiPolygon":
+		member = "coordinates"
+	default:
+		return fmt.Errorf("render: GeoJSON object has an unknown type %q", typ)
+	}
+
+	// Only the geometry of a feature may be null, but it must be there.
+	raw, ok := o[member]
+	if !ok || (member != "geometry" && string(raw) == "null") {
+		return fmt.Errorf("render: GeoJSON %s has no %s", typ, member)
+	}
+	return nil
+}
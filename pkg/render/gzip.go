@@ -0,0 +1,77 @@
+package render
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// Gzip is a wrapper engine compressing the output of another engine.
+//
+// Since engines never see the request, the caller passes the request's
+// Accept-Encoding header value and the response is only compressed when
+// it allows gzip. Bodies smaller than MinSize are sent uncompressed.
+// Vary: Accept-Encoding is added to HTTP responses either way.
+// A zero Level uses gzip.DefaultCompression, so gzip.NoCompression can't be
+// selected: leave the engine out to send bodies uncompressed.
+type Gzip struct {
+	Engine         Engine
+	Level          int
+	MinSize        int
+	AcceptEncoding string
+}
+
+// Render a gzip compressed response.
+func (g Gzip) Render(w io.Writer, v interface{}) error {
+	hw, ok := w.(http.ResponseWriter)
+	if !ok {
+		return g.Engine.Render(w, v)
+	}
+	if !acceptsGzip(g.AcceptEncoding) {
+		addVary(hw.Header(), "Accept-Encoding")
+		return g.Engine.Render(w, v)
+	}
+
+	// Render the inner engine into a buffer so its Head.Write can't clobber our headers.
+	rec := newBufferedResponseWriter()
+	if err := g.Engine.Render(rec, v); err != nil {
+		return err
+	}
+
+	rec.copyHeader(hw)
+	addVary(hw.Header(), "Accept-Encoding")
+	if rec.body.Len() < g.MinSize {
+		hw.Header().Set(ContentLength, strconv.Itoa(rec.body.Len()))
+		if rec.status != 0 {
+			hw.WriteHeader(rec.status)
+		}
+		_, err := rec.body.WriteTo(hw)
+		return err
+	}
+
+	level := g.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	gz, err := gzip.NewWriterLevel(hw, level)
+	if err != nil {
+		return err
+	}
+
+	hw.Header().Del(ContentLength)
+	hw.Header().Set(ContentEncoding, "gzip")
+	if rec.status != 0 {
+		hw.WriteHeader(rec.status)
+	}
+	if _, err := rec.body.WriteTo(gz); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// acceptsGzip reports whether the given Accept-Encoding header value allows gzip,
+// "*" applying only when gzip isn't listed.
+func acceptsGzip(acceptEncoding string) bool {
+	return acceptQuality(parseAccept(acceptEncoding), "gzip") > 0
+}
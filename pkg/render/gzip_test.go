@@ -0,0 +1,178 @@
+package render
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptsGzip(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		acceptEncoding string
+		expected       bool
+	}{
+		{
+			desc:           "empty",
+			acceptEncoding: "",
+			expected:       false,
+		},
+		{
+			desc:           "gzip",
+			acceptEncoding: "gzip, deflate",
+			expected:       true,
+		},
+		{
+			desc:           "upper case gzip",
+			acceptEncoding: "GZIP",
+			expected:       true,
+		},
+		{
+			desc:           "refused gzip",
+			acceptEncoding: "gzip;q=0",
+			expected:       false,
+		},
+		{
+			desc:           "wildcard",
+			acceptEncoding: "br, *;q=0.5",
+			expected:       true,
+		},
+		{
+			desc:           "refused gzip and wildcard",
+			acceptEncoding: "gzip;q=0, *",
+			expected:       false,
+		},
+		{
+			desc:           "refused wildcard",
+			acceptEncoding: "*;q=0",
+			expected:       false,
+		},
+		{
+			desc:           "other coding",
+			acceptEncoding: "br",
+			expected:       false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, acceptsGzip(test.acceptEncoding))
+		})
+	}
+}
+
+// emptyEngine is an engine writing neither a status nor a body.
+type emptyEngine struct{}
+
+func (emptyEngine) Render(io.Writer, interface{}) error {
+	return nil
+}
+
+func TestGzip_Render(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		engine           Gzip
+		expectedStatus   int
+		expectedEncoding string
+	}{
+		{
+			desc:             "compressed",
+			engine:           Gzip{Engine: Text{Head: Head{Status: http.StatusCreated}}, AcceptEncoding: "gzip"},
+			expectedStatus:   http.StatusCreated,
+			expectedEncoding: "gzip",
+		},
+		{
+			desc:             "compressed without a status",
+			engine:           Gzip{Engine: Text{}, AcceptEncoding: "gzip", Level: gzip.BestSpeed},
+			expectedStatus:   http.StatusOK,
+			expectedEncoding: "gzip",
+		},
+		{
+			desc:           "smaller than MinSize",
+			engine:         Gzip{Engine: Text{Head: Head{Status: http.StatusCreated}}, AcceptEncoding: "gzip", MinSize: 1024},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			desc:           "smaller than MinSize without a status",
+			engine:         Gzip{Engine: Text{}, AcceptEncoding: "gzip", MinSize: 1024},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "gzip not accepted",
+			engine:         Gzip{Engine: Text{Head: Head{Status: http.StatusOK}}, AcceptEncoding: "br"},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			err := test.engine.Render(recorder, "hello")
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedEncoding, recorder.Header().Get(ContentEncoding))
+			assert.Equal(t, "Accept-Encoding", recorder.Header().Get(Vary))
+
+			body := recorder.Body.String()
+			if test.expectedEncoding == "gzip" {
+				gz, err := gzip.NewReader(recorder.Body)
+				require.NoError(t, err)
+				b, err := ioutil.ReadAll(gz)
+				require.NoError(t, err)
+				body = string(b)
+			}
+			assert.Equal(t, "hello", body)
+		})
+	}
+}
+
+func TestGzip_Render_noStatus(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		engine Gzip
+	}{
+		{
+			desc:   "compressed",
+			engine: Gzip{Engine: emptyEngine{}, AcceptEncoding: "gzip"},
+		},
+		{
+			desc:   "smaller than MinSize",
+			engine: Gzip{Engine: emptyEngine{}, AcceptEncoding: "gzip", MinSize: 1024},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			err := test.engine.Render(recorder, nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+		})
+	}
+}
+
+func TestGzip_Render_error(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	err := Gzip{Engine: JSON{Head: Head{Status: http.StatusOK}}, AcceptEncoding: "gzip"}.Render(recorder, make(chan int))
+	require.Error(t, err)
+
+	assert.Empty(t, recorder.Header())
+	assert.Empty(t, recorder.Body.String())
+}
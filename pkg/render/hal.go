@@ -0,0 +1,56 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// HALLink is a HAL link object.
+type HALLink struct {
+	Href      string `json:"href"`
+	Templated bool   `json:"templated,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Title     string `json:"title,omitempty"`
+}
+
+// HALLinks maps link relations, such as "self", to their link.
+type HALLinks map[string]HALLink
+
+// HALEmbedded maps link relations to embedded resources or slices of them.
+type HALEmbedded map[string]interface{}
+
+// HALResource holds the reserved HAL members. Embed it in a struct to add
+// _links and _embedded to its JSON object.
+type HALResource struct {
+	Links    HALLinks    `json:"_links,omitempty"`
+	Embedded HALEmbedded `json:"_embedded,omitempty"`
+}
+
+// HAL built-in renderer for HAL+JSON hypermedia responses.
+type HAL struct {
+	Head
+	Indent bool
+}
+
+// Render a HAL+JSON response.
+func (h HAL) Render(w io.Writer, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if !bytes.HasPrefix(b, []byte("{")) {
+		return fmt.Errorf("render: HAL expects a value marshaling to a JSON object, got %T", v)
+	}
+
+	h.Head.setDefaultContentType(ContentHALJSON)
+
+	j := JSON{
+		Head:   h.Head,
+		Indent: h.Indent,
+	}
+
+	return j.Render(w, json.RawMessage(b))
+}
@@ -18,4 +18,10 @@ var helperFuncs = template.FuncMap{
 	"current": func() (string, error) {
 		return "", nil
 	},
+	"nonce": func() (string, error) {
+		return "", fmt.Errorf("nonce called with no nonce enabled")
+	},
+	"T": func(string, ...interface{}) (string, error) {
+		return "", fmt.Errorf("T called with no Lang and Translate set")
+	},
 }
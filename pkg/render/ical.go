@@ -0,0 +1,174 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Calendar is an iCalendar object rendered by the ICal engine.
+type Calendar struct {
+	// ProdID identifies the product which created the calendar.
+	ProdID string
+	// Name is the display name of the calendar, written as X-WR-CALNAME.
+	Name   string
+	Events []CalendarEvent
+}
+
+// CalendarEvent is a single event of a Calendar. All day events only keep the
+// date of Start and End, End being exclusive.
+type CalendarEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	// Stamp is when the event was created, defaulting to the time of rendering.
+	Stamp time.Time
+}
+
+// ICal built-in renderer for RFC 5545 iCalendar data.
+type ICal struct {
+	Head
+}
+
+const defaultICalProdID = "-//unrolled//render//EN"
+
+// Render an iCalendar response.
+func (i ICal) Render(w io.Writer, v interface{}) error {
+	var cal Calendar
+	switch c := v.(type) {
+	case Calendar:
+		cal = c
+	case *Calendar:
+		if c == nil {
+			return errors.New("render: ICal cannot render a nil *Calendar")
+		}
+		cal = *c
+	default:
+		return fmt.Errorf("render: ICal expects Calendar, got %T", v)
+	}
+
+	// Retrieve a buffer from the pool to write to.
+	out := bufPool.Get()
+	defer bufPool.Put(out)
+
+	prodID := cal.ProdID
+	if prodID == "" {
+		prodID = defaultICalProdID
+	}
+
+	writeContentLine(out, "BEGIN:VCALENDAR")
+	writeContentLine(out, "VERSION:2.0")
+	writeContentLine(out, "PRODID:"+escapeText(prodID))
+	if cal.Name != "" {
+		writeContentLine(out, "X-WR-CALNAME:"+escapeText(cal.Name))
+	}
+	now := time.Now()
+	for _, e := range cal.Events {
+		if e.UID == "" {
+			return fmt.Errorf("render: ICal event %q has no UID", e.Summary)
+		}
+		stamp := e.Stamp
+		if stamp.IsZero() {
+			stamp = now
+		}
+
+		writeContentLine(out, "BEGIN:VEVENT")
+		writeContentLine(out, "UID:"+escapeText(e.UID))
+		writeContentLine(out, "DTSTAMP:"+icalDateTime(stamp))
+		if !e.Start.IsZero() {
+			writeContentLine(out, icalDateProperty("DTSTART", e.Start, e.AllDay))
+		}
+		if !e.End.IsZero() {
+			writeContentLine(out, icalDateProperty("DTEND", e.End, e.AllDay))
+		}
+		if e.Summary != "" {
+			writeContentLine(out, "SUMMARY:"+escapeText(e.Summary))
+		}
+		if e.Description != "" {
+			writeContentLine(out, "DESCRIPTION:"+escapeText(e.Description))
+		}
+		if e.Location != "" {
+			writeContentLine(out, "LOCATION:"+escapeText(e.Location))
+		}
+		if e.URL != "" {
+			if err := checkURIValue(e.URL); err != nil {
+				return err
+			}
+			writeContentLine(out, "URL:"+e.URL)
+		}
+		writeContentLine(out, "END:VEVENT")
+	}
+	writeContentLine(out, "END:VCALENDAR")
+
+	if hw, ok := w.(http.ResponseWriter); ok {
+		hw.Header().Set(ContentLength, strconv.Itoa(out.Len()))
+		i.Head.setDefaultContentType(ContentCalendar)
+		i.Head.Write(hw)
+	}
+	_, err := out.WriteTo(w)
+	return err
+}
+
+// icalDateProperty formats a DTSTART or DTEND property.
+func icalDateProperty(name string, t time.Time, allDay bool) string {
+	if allDay {
+		return name + ";VALUE=DATE:" + t.Format("20060102")
+	}
+	return name + ":" + icalDateTime(t)
+}
+
+// icalDateTime formats t as an UTC date-time.
+func icalDateTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escapeText escapes a TEXT property value as defined by RFC 5545 and RFC 6350.
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// checkURIValue checks a URI property value, which is written as is and so can't
+// hold line breaks starting another property.
+func checkURIValue(uri string) error {
+	if strings.ContainsAny(uri, "\r\n") {
+		return fmt.Errorf("render: URI %q contains a line break", uri)
+	}
+	return nil
+}
+
+// writeContentLine writes a CRLF terminated content line, folded so that no
+// line exceeds 75 octets. Lines are never folded inside a UTF-8 sequence.
+func writeContentLine(buf *bytes.Buffer, line string) {
+	limit := 75
+	for len(line) > limit {
+		n := limit
+		for n > 0 && !utf8.RuneStart(line[n]) {
+			n--
+		}
+		buf.WriteString(line[:n])
+		buf.WriteString("\r\n ")
+		line = line[n:]
+		// The leading space of continuation lines counts towards the limit.
+		limit = 74
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
@@ -0,0 +1,96 @@
+package render
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestICal_Render(t *testing.T) {
+	stamp := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	cal := Calendar{
+		ProdID: "-//Test//EN",
+		Events: []CalendarEvent{{
+			UID:     "1",
+			Stamp:   stamp,
+			Start:   stamp,
+			Summary: "a; b, c\nd",
+			URL:     "https://example.com/?a=1;b=2",
+		}},
+	}
+	expected := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Test//EN\r\n" +
+		"BEGIN:VEVENT\r\nUID:1\r\nDTSTAMP:20190102T030405Z\r\nDTSTART:20190102T030405Z\r\n" +
+		"SUMMARY:" + `a\; b\, c\nd` + "\r\nURL:https://example.com/?a=1;b=2\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	testCases := []struct {
+		desc  string
+		value interface{}
+	}{
+		{
+			desc:  "calendar",
+			value: cal,
+		},
+		{
+			desc:  "pointer to a calendar",
+			value: &cal,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			err := ICal{}.Render(buf, test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, buf.String())
+		})
+	}
+}
+
+func TestICal_Render_invalidValue(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		value interface{}
+	}{
+		{
+			desc:  "nil calendar",
+			value: (*Calendar)(nil),
+		},
+		{
+			desc:  "nil",
+			value: nil,
+		},
+		{
+			desc:  "event without UID",
+			value: Calendar{Events: []CalendarEvent{{Summary: "a"}}},
+		},
+		{
+			desc:  "URL with a line break",
+			value: Calendar{Events: []CalendarEvent{{UID: "1", URL: "https://example.com/\r\nATTENDEE:mailto:a@example.com"}}},
+		},
+		{
+			desc:  "URL with a carriage return",
+			value: Calendar{Events: []CalendarEvent{{UID: "1", URL: "https://example.com/\rX"}}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			err := ICal{}.Render(buf, test.value)
+			require.Error(t, err)
+
+			assert.Empty(t, buf.String())
+		})
+	}
+}
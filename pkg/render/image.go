@@ -0,0 +1,71 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Image built-in renderer, encoding an image.Image as a PNG, JPEG or GIF image.
+// Format is one of "png", the default, "jpeg" (or "jpg") and "gif". Quality
+// ranges from 1 to 100 and applies to JPEG only, zero meaning jpeg.DefaultQuality.
+type Image struct {
+	Head
+	Format  string
+	Quality int
+}
+
+// Render an image response.
+func (i Image) Render(w io.Writer, v interface{}) error {
+	img, ok := v.(image.Image)
+	if !ok {
+		return fmt.Errorf("render: Image expects image.Image, got %T", v)
+	}
+	if isNil(img) {
+		return fmt.Errorf("render: Image cannot render a nil %T", v)
+	}
+
+	// Retrieve a buffer from the pool to encode into.
+	buf := bufPool.Get()
+	defer bufPool.Put(buf)
+
+	var contentType string
+	var err error
+	switch strings.ToLower(i.Format) {
+	case "", "png":
+		contentType = ContentPNG
+		err = png.Encode(buf, img)
+	case "jpeg", "jpg":
+		contentType = ContentJPEG
+		quality := i.Quality
+		if quality == 0 {
+			quality = jpeg.DefaultQuality
+		}
+		err = jpeg.Encode(buf, img, &jpeg.Options{Quality: quality})
+	case "gif":
+		contentType = ContentGIF
+		err = gif.Encode(buf, img, nil)
+	default:
+		return fmt.Errorf("render: Image format %q is not supported", i.Format)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Image encoded fine, write out the result.
+	if hw, ok := w.(http.ResponseWriter); ok {
+		if i.Head.ContentType == "" {
+			i.Head.ContentType = contentType
+		}
+		hw.Header().Set(ContentLength, strconv.Itoa(buf.Len()))
+		i.Head.Write(hw)
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
@@ -0,0 +1,114 @@
+package render
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImage_Render(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(1, 1, color.White)
+
+	testCases := []struct {
+		desc        string
+		engine      Image
+		contentType string
+		decode      func(io.Reader) (image.Image, error)
+	}{
+		{
+			desc:        "default",
+			contentType: ContentPNG,
+			decode:      png.Decode,
+		},
+		{
+			desc:        "JPEG",
+			engine:      Image{Format: "JPG", Quality: 90},
+			contentType: ContentJPEG,
+			decode:      jpeg.Decode,
+		},
+		{
+			desc:        "GIF",
+			engine:      Image{Format: "gif"},
+			contentType: ContentGIF,
+			decode:      gif.Decode,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			engine := test.engine
+			engine.Head.Status = http.StatusOK
+			recorder := httptest.NewRecorder()
+			err := engine.Render(recorder, img)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.contentType, recorder.Header().Get(ContentType))
+			assert.Equal(t, strconv.Itoa(recorder.Body.Len()), recorder.Header().Get(ContentLength))
+
+			decoded, err := test.decode(bytes.NewReader(recorder.Body.Bytes()))
+			require.NoError(t, err)
+			assert.Equal(t, img.Bounds(), decoded.Bounds())
+		})
+	}
+}
+
+func TestImage_Render_invalidValue(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		engine Image
+		value  interface{}
+	}{
+		{
+			desc:  "nil",
+			value: nil,
+		},
+		{
+			desc:  "nil image",
+			value: (*image.RGBA)(nil),
+		},
+		{
+			desc:  "not an image",
+			value: []byte("png"),
+		},
+		{
+			desc:   "unsupported format",
+			engine: Image{Format: "webp"},
+			value:  image.NewRGBA(image.Rect(0, 0, 1, 1)),
+		},
+		{
+			desc:  "empty image",
+			value: image.NewRGBA(image.Rect(0, 0, 0, 0)),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			engine := test.engine
+			engine.Head.Status = http.StatusOK
+			recorder := httptest.NewRecorder()
+			err := engine.Render(recorder, test.value)
+			require.Error(t, err)
+
+			assert.Empty(t, recorder.Header())
+			assert.Empty(t, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// INI built-in renderer for configuration files.
+//
+// The value is a struct or a map with string keys. Nested structs and maps
+// become [section] blocks, named with dotted paths when nested further, e.g.
+// [server.tls]. Key names are read from the `ini` struct tag, falling back to
+// the field name, and honor the "-" and omitempty options. Slices are written
+// as comma separated lists and nil values are left out.
+type INI struct {
+	Head
+}
+
+// iniSection is a section of INI output, the unnamed one being the global section.
+type iniSection struct {
+	name     string
+	keys     [][2]string
+	sections []*iniSection
+}
+
+// Render an INI response.
+func (i INI) Render(w io.Writer, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if !isINISection(rv) {
+		return fmt.Errorf("render: INI expects a struct or a string map, got %T", v)
+	}
+
+	root := &iniSection{}
+	if err := root.fill(rv); err != nil {
+		return err
+	}
+
+	// Retrieve a buffer from the pool to write to.
+	buf := bufPool.Get()
+	defer bufPool.Put(buf)
+
+	root.write(buf)
+
+	if hw, ok := w.(http.ResponseWriter); ok {
+		hw.Header().Set(ContentLength, strconv.Itoa(buf.Len()))
+		i.Head.setDefaultContentType(ContentText)
+		i.Head.Write(hw)
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// fill adds the fields of the struct or map rv to the section.
+func (s *iniSection) fill(rv reflect.Value) error {
+	if rv.Kind() == reflect.Map {
+		if rv.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("render: INI cannot encode %s, keys must be strings", rv.Type())
+		}
+		keys := make([]string, 0, rv.Len())
+		for _, k := range rv.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			if err := s.add(k, rv.MapIndex(reflect.ValueOf(k).Convert(rv.Type().Key()))); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name, opts := f.Name, ""
+		if tag := f.Tag.Get("ini"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if idx := strings.Index(tag, ","); idx != -1 {
+				tag, opts = tag[:idx], tag[idx+1:]
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+
+		fv := rv.Field(i)
+		if hasOption(opts, "omitempty") && isEmptyValue(fv) {
+			continue
+		}
+		if err := s.add(name, fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add adds a key, or a subsection for structs and maps.
+func (s *iniSection) add(name string, rv reflect.Value) error {
+	// Names are written as is, these characters would start another key or section.
+	if strings.ContainsAny(name, "\r\n[]=;#") {
+		return fmt.Errorf("render: INI name %q contains an invalid character", name)
+	}
+
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+
+	if isINISection(rv) {
+		if rv.Kind() == reflect.Map && rv.IsNil() {
+			return nil
+		}
+		sub := &iniSection{name: name}
+		if s.name != "" {
+			sub.name = s.name + "." + name
+		}
+		s.sections = append(s.sections, sub)
+		return sub.fill(rv)
+	}
+
+	var value string
+	switch {
+	case (rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8) || rv.Kind() == reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil
+		}
+		values := make([]string, rv.Len())
+		for i := range values {
+			values[i] = formValue(rv.Index(i))
+		}
+		value = strings.Join(values, ",")
+	default:
+		value = formValue(rv)
+	}
+	s.keys = append(s.keys, [2]string{name, value})
+	return nil
+}
+
+// write writes the keys of the section, then its subsections.
+func (s *iniSection) write(buf *bytes.Buffer) {
+	if s.name != "" {
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString("[" + s.name + "]\n")
+	}
+	for _, kv := range s.keys {
+		buf.WriteString(kv[0] + " = " + iniQuote(kv[1]) + "\n")
+	}
+	for _, sub := range s.sections {
+		sub.write(buf)
+	}
+}
+
+// isINISection reports whether rv is written as a section: a struct which
+// doesn't marshal itself as text, such as time.Time, or a map.
+func isINISection(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Struct:
+		return !rv.Type().Implements(textMarshalerType)
+	case reflect.Map:
+		return true
+	}
+	return false
+}
+
+// iniQuote quotes values which would otherwise be misread: surrounded by spaces,
+// holding comment characters, quotes or line breaks.
+func iniQuote(value string) string {
+	if value == "" || (strings.TrimSpace(value) == value && !strings.ContainsAny(value, ";#\"\r\n")) {
+		return value
+	}
+	return strconv.Quote(value)
+}
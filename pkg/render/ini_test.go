@@ -0,0 +1,115 @@
+package render
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestINI_Render(t *testing.T) {
+	type tls struct {
+		Cert string `ini:"cert"`
+	}
+	type server struct {
+		Host  string   `ini:"host"`
+		Ports []int    `ini:"ports"`
+		Note  string   `ini:"note,omitempty"`
+		Skip  string   `ini:"-"`
+		TLS   *tls     `ini:"tls"`
+		Extra []string `ini:"extra"`
+	}
+
+	testCases := []struct {
+		desc     string
+		value    interface{}
+		expected string
+	}{
+		{
+			desc:     "struct",
+			value:    struct{ Name string }{Name: " padded; value"},
+			expected: "Name = \" padded; value\"\n",
+		},
+		{
+			desc: "nested structs",
+			value: map[string]interface{}{
+				"debug":  true,
+				"server": &server{Host: "localhost", Ports: []int{80, 443}, Skip: "x", TLS: &tls{Cert: "a.pem"}},
+			},
+			expected: "debug = true\n\n[server]\nhost = localhost\nports = 80,443\n\n[server.tls]\ncert = a.pem\n",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			err := INI{}.Render(buf, test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, buf.String())
+		})
+	}
+}
+
+func TestINI_Render_invalidValue(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		value interface{}
+	}{
+		{
+			desc:  "nil",
+			value: nil,
+		},
+		{
+			desc:  "nil pointer",
+			value: (*struct{ Name string })(nil),
+		},
+		{
+			desc:  "scalar",
+			value: 42,
+		},
+		{
+			desc:  "non-string keys",
+			value: map[int]string{1: "a"},
+		},
+		{
+			desc:  "key starting a section",
+			value: map[string]string{"a\n[admin]": "b"},
+		},
+		{
+			desc:  "key holding an equal sign",
+			value: map[string]string{"a=b": "c"},
+		},
+		{
+			desc:  "section name holding a bracket",
+			value: map[string]map[string]string{"a]": {"b": "c"}},
+		},
+		{
+			desc:  "key holding a comment character",
+			value: map[string]string{"a;b": "c"},
+		},
+		{
+			desc: "tag name holding a carriage return",
+			value: struct {
+				A string `ini:"a\rb"`
+			}{A: "c"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			err := INI{}.Render(buf, test.value)
+			require.Error(t, err)
+
+			assert.Empty(t, buf.String())
+		})
+	}
+}
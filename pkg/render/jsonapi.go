@@ -0,0 +1,148 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// JSONAPI built-in renderer for JSON:API documents. The value is a struct, a
+// pointer to a struct or a slice of them, shaped into resource objects through
+// `jsonapi` struct tags:
+//
+//	type Article struct {
+//		ID    int    `jsonapi:"primary,articles"`
+//		Title string `jsonapi:"attr,title"`
+//		Body  string `jsonapi:"attr,body,omitempty"`
+//	}
+//
+// Fields without a `jsonapi` tag are left out.
+type JSONAPI struct {
+	Head
+	Indent bool
+}
+
+// jsonAPIDocument is a JSON:API top-level document.
+type jsonAPIDocument struct {
+	Data interface{} `json:"data"`
+}
+
+// jsonAPIResource is a JSON:API resource object.
+type jsonAPIResource struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// Render a JSON:API response.
+func (j JSONAPI) Render(w io.Writer, v interface{}) error {
+	data, err := jsonAPIData(reflect.ValueOf(v))
+	if err != nil {
+		return err
+	}
+
+	// JSON:API forbids media type parameters, so there's no charset by default.
+	if j.Head.ContentType == "" {
+		j.Head.ContentType = ContentJSONAPI
+	}
+
+	e := JSON{
+		Head:   j.Head,
+		Indent: j.Indent,
+	}
+
+	return e.Render(w, jsonAPIDocument{Data: data})
+}
+
+// jsonAPIData converts a resource or a collection of resources.
+func jsonAPIData(rv reflect.Value) (interface{}, error) {
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		resources := make([]*jsonAPIResource, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			resource, err := jsonAPIResourceOf(rv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			if resource != nil {
+				resources = append(resources, resource)
+			}
+		}
+		return resources, nil
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil, nil
+		}
+	}
+
+	resource, err := jsonAPIResourceOf(rv)
+	if err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// jsonAPIResourceOf converts a struct, or a pointer to one, into a resource object.
+// A nil value converts to a nil resource.
+func jsonAPIResourceOf(rv reflect.Value) (*jsonAPIResource, error) {
+	if rv.Kind() == reflect.Interface {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil, nil
+	}
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("render: JSONAPI expects a struct or a slice of structs, got %s", rv.Type())
+	}
+
+	var resource *jsonAPIResource
+	attributes := map[string]interface{}{}
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("jsonapi")
+		if tag == "" || f.PkgPath != "" {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		if len(parts) < 2 || parts[1] == "" {
+			return nil, fmt.Errorf("render: JSONAPI tag %q of %s.%s has no name", tag, t, f.Name)
+		}
+
+		fv := rv.Field(i)
+		switch parts[0] {
+		case "primary":
+			// Identifiers held by pointers, e.g. *string, are formatted as the value pointed to.
+			for fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+				if fv.IsNil() {
+					return nil, fmt.Errorf("render: JSONAPI primary field %s.%s is nil", t, f.Name)
+				}
+				fv = fv.Elem()
+			}
+			resource = &jsonAPIResource{Type: parts[1], ID: fmt.Sprint(fv.Interface())}
+		case "attr":
+			if hasOption(strings.Join(parts[2:], ","), "omitempty") && isEmptyValue(fv) {
+				continue
+			}
+			attributes[parts[1]] = fv.Interface()
+		default:
+			return nil, fmt.Errorf("render: JSONAPI tag %q of %s.%s is unknown", tag, t, f.Name)
+		}
+	}
+
+	if resource == nil {
+		return nil, fmt.Errorf("render: JSONAPI expects %s to have a `jsonapi:\"primary,<type>\"` field", t)
+	}
+	if len(attributes) > 0 {
+		resource.Attributes = attributes
+	}
+	return resource, nil
+}
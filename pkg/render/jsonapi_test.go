@@ -0,0 +1,101 @@
+package render
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONAPI_Render_pointerID(t *testing.T) {
+	type article struct {
+		ID    *string `jsonapi:"primary,articles"`
+		Title string  `jsonapi:"attr,title"`
+	}
+	type comment struct {
+		ID *int `jsonapi:"primary,comments"`
+	}
+
+	id, number := "a1", 42
+
+	testCases := []struct {
+		desc     string
+		value    interface{}
+		expected string
+	}{
+		{
+			desc:     "string pointer",
+			value:    article{ID: &id, Title: "title"},
+			expected: `{"data":{"type":"articles","id":"a1","attributes":{"title":"title"}}}`,
+		},
+		{
+			desc:     "int pointer",
+			value:    []comment{{ID: &number}},
+			expected: `{"data":[{"type":"comments","id":"42"}]}`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			err := JSONAPI{}.Render(buf, test.value)
+			require.NoError(t, err)
+
+			assert.JSONEq(t, test.expected, buf.String())
+		})
+	}
+}
+
+func TestJSONAPI_Render_nilID(t *testing.T) {
+	type article struct {
+		ID *string `jsonapi:"primary,articles"`
+	}
+
+	err := JSONAPI{}.Render(&bytes.Buffer{}, article{})
+	assert.Error(t, err)
+}
+
+func TestJSONAPI_Render_nil(t *testing.T) {
+	type article struct {
+		ID string `jsonapi:"primary,articles"`
+	}
+
+	testCases := []struct {
+		desc     string
+		value    interface{}
+		expected string
+	}{
+		{
+			desc:     "nil",
+			value:    nil,
+			expected: `{"data":null}`,
+		},
+		{
+			desc:     "nil pointer",
+			value:    (*article)(nil),
+			expected: `{"data":null}`,
+		},
+		{
+			desc:     "nil elements",
+			value:    []interface{}{nil, article{ID: "a1"}, (*article)(nil)},
+			expected: `{"data":[{"type":"articles","id":"a1"}]}`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			err := JSONAPI{}.Render(buf, test.value)
+			require.NoError(t, err)
+
+			assert.JSONEq(t, test.expected, buf.String())
+		})
+	}
+}
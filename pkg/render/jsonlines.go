@@ -0,0 +1,83 @@
+package render
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+// JSONLines built-in renderer, writing one JSON value per line (NDJSON).
+type JSONLines struct {
+	Head
+}
+
+// Render a JSON Lines response.
+func (j JSONLines) Render(w io.Writer, v interface{}) error {
+	return j.RenderCtx(context.Background(), w, v)
+}
+
+// RenderCtx renders a JSON Lines response from a slice, array or channel,
+// stopping early when ctx is done.
+func (j JSONLines) RenderCtx(ctx context.Context, w io.Writer, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+	case reflect.Chan:
+		if rv.Type().ChanDir()&reflect.RecvDir == 0 {
+			return fmt.Errorf("render: JSONLines cannot receive from %T", v)
+		}
+	default:
+		return fmt.Errorf("render: JSONLines expects a slice, array or channel, got %T", v)
+	}
+
+	if hw, ok := w.(http.ResponseWriter); ok {
+		j.Head.setDefaultContentType(ContentNDJSON)
+		j.Head.Write(hw)
+	}
+
+	enc := json.NewEncoder(w)
+	return eachElement(ctx, rv, func(e interface{}) error {
+		// Encode terminates every value with a new line.
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+		flush(w)
+		return nil
+	})
+}
+
+// eachElement calls fn for each element of the slice, array or channel rv,
+// returning the context error as soon as ctx is done.
+func eachElement(ctx context.Context, rv reflect.Value, fn func(interface{}) error) error {
+	if rv.Kind() != reflect.Chan {
+		for i := 0; i < rv.Len(); i++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := fn(rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: rv},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+	}
+	for {
+		chosen, e, ok := reflect.Select(cases)
+		if chosen == 1 {
+			return ctx.Err()
+		}
+		if !ok {
+			return nil
+		}
+		if err := fn(e.Interface()); err != nil {
+			return err
+		}
+	}
+}
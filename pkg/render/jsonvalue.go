@@ -0,0 +1,427 @@
+package render
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// TimeFormatUnix renders time.Time values as Unix timestamps in seconds.
+	TimeFormatUnix = "unix"
+	// TimeFormatUnixMilli renders time.Time values as Unix timestamps in milliseconds.
+	TimeFormatUnixMilli = "unixmilli"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// jsonConverter converts values into a generic representation which encoding/json
+// marshals the same way it would the original, except that struct fields are read
+// from tagName tags and time.Time values are formatted with timeFormat.
+type jsonConverter struct {
+	tagName    string
+	timeFormat string
+	// visiting holds the pointers, maps and slices being converted, to detect cycles.
+	visiting map[jsonVisit]bool
+}
+
+// jsonVisit identifies a pointer, map or slice, slices of different lengths
+// sharing the same array being different values.
+type jsonVisit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// jsonField is a single member of a jsonObject.
+type jsonField struct {
+	name  string
+	value interface{}
+}
+
+// jsonObject is a JSON object keeping the order of its fields, as structs do.
+type jsonObject []jsonField
+
+// MarshalJSON implements json.Marshaler.
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(f.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// convert converts v, failing when it holds a cycle, which encoding/json rejects too.
+func (c jsonConverter) convert(v interface{}) (interface{}, error) {
+	c.visiting = map[jsonVisit]bool{}
+	return c.value(reflect.ValueOf(v))
+}
+
+func (c jsonConverter) value(rv reflect.Value) (interface{}, error) {
+	if !rv.IsValid() {
+		return nil, nil
+	}
+
+	if rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return c.value(rv.Elem())
+	}
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Type() == timeType {
+		rv = rv.Elem()
+	}
+	if rv.Type() == timeType {
+		return c.time(rv.Interface().(time.Time)), nil
+	}
+	// Types marshaling themselves are left to encoding/json, which also calls the
+	// pointer methods of addressable values.
+	if rv.Type().Implements(jsonMarshalerType) || rv.Type().Implements(textMarshalerType) {
+		return rv.Interface(), nil
+	}
+	if rv.Kind() != reflect.Ptr && rv.CanAddr() {
+		if pt := reflect.PtrTo(rv.Type()); pt.Implements(jsonMarshalerType) || pt.Implements(textMarshalerType) {
+			return rv.Addr().Interface(), nil
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			// Byte slices are base64 encoded as is.
+			return rv.Interface(), nil
+		}
+
+		visit := jsonVisit{ptr: rv.Pointer(), typ: rv.Type()}
+		if rv.Kind() == reflect.Slice {
+			visit.len = rv.Len()
+		}
+		if c.visiting[visit] {
+			return nil, fmt.Errorf("render: JSON cannot convert a cycle via %s", rv.Type())
+		}
+		c.visiting[visit] = true
+		defer delete(c.visiting, visit)
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		return c.value(rv.Elem())
+	case reflect.Struct:
+		return c.object(rv)
+	case reflect.Map:
+		m := make(map[string]interface{}, rv.Len())
+		for _, key := range rv.MapKeys() {
+			value, err := c.value(rv.MapIndex(key))
+			if err != nil {
+				return nil, err
+			}
+			m[mapKey(key)] = value
+		}
+		return m, nil
+	case reflect.Slice, reflect.Array:
+		s := make([]interface{}, rv.Len())
+		for i := range s {
+			value, err := c.value(rv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			s[i] = value
+		}
+		return s, nil
+	}
+	return rv.Interface(), nil
+}
+
+// structField is a field of a struct, or of the structs it embeds, along with
+// what encoding/json needs to pick between fields of the same name.
+type structField struct {
+	name   string
+	opts   string
+	value  reflect.Value
+	depth  int
+	tagged bool
+	// omit is set for the fields of nil embedded pointers, which still hide
+	// the fields they collide with.
+	omit bool
+}
+
+// object converts a struct into a jsonObject. Fields of embedded structs are
+// promoted in place, names colliding being resolved like encoding/json does:
+// the shallowest field wins, a tagged one winning among several at the same
+// depth, and all of them are dropped when that's still ambiguous.
+func (c jsonConverter) object(rv reflect.Value) (jsonObject, error) {
+	var fields []structField
+	c.fields(rv, 0, false, map[reflect.Type]bool{}, &fields)
+
+	byName := map[string][]int{}
+	for i, f := range fields {
+		byName[f.name] = append(byName[f.name], i)
+	}
+	dominant := make(map[string]int, len(byName))
+	for name, indexes := range byName {
+		dominant[name] = dominantField(fields, indexes)
+	}
+
+	obj := make(jsonObject, 0, len(fields))
+	for i, f := range fields {
+		if dominant[f.name] != i || f.omit {
+			continue
+		}
+		if hasOption(f.opts, "omitempty") && isEmptyValue(f.value) {
+			continue
+		}
+
+		value, err := c.value(f.value)
+		if err != nil {
+			return nil, err
+		}
+		if hasOption(f.opts, "string") {
+			switch f.value.Kind() {
+			case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+				reflect.Float32, reflect.Float64:
+				value = fmt.Sprint(value)
+			case reflect.String:
+				b, _ := json.Marshal(value)
+				value = string(b)
+			}
+		}
+		obj = append(obj, jsonField{name: f.name, value: value})
+	}
+	return obj, nil
+}
+
+// dominantField returns the index of the field winning among the ones of the
+// same name at indexes, or -1 when none does.
+func dominantField(fields []structField, indexes []int) int {
+	var shallowest, tagged []int
+	for _, i := range indexes {
+		if len(shallowest) > 0 && fields[i].depth > fields[shallowest[0]].depth {
+			continue
+		}
+		if len(shallowest) > 0 && fields[i].depth < fields[shallowest[0]].depth {
+			shallowest, tagged = nil, nil
+		}
+		shallowest = append(shallowest, i)
+		if fields[i].tagged {
+			tagged = append(tagged, i)
+		}
+	}
+
+	switch {
+	case len(shallowest) == 1:
+		return shallowest[0]
+	case len(tagged) == 1:
+		return tagged[0]
+	}
+	return -1
+}
+
+// fields lists the fields of rv in encoding/json order, expanding the embedded
+// structs whose type isn't already being expanded.
+func (c jsonConverter) fields(rv reflect.Value, depth int, omit bool, expanding map[reflect.Type]bool, fields *[]structField) {
+	t := rv.Type()
+	expanding[t] = true
+	defer delete(expanding, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get(c.tagName)
+		if tag == "-" {
+			continue
+		}
+
+		name, opts := tag, ""
+		if idx := strings.Index(tag, ","); idx != -1 {
+			name, opts = tag[:idx], tag[idx+1:]
+		}
+
+		fv := rv.Field(i)
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if expanding[ft] {
+					continue
+				}
+				// Like encoding/json, promote the exported fields of unexported
+				// embedded structs too, reflection allowing to read those.
+				embeddedOmit := omit
+				if fv.Kind() == reflect.Ptr {
+					if fv.IsNil() {
+						fv, embeddedOmit = reflect.Zero(ft), true
+					} else {
+						fv = fv.Elem()
+					}
+				}
+				c.fields(fv, depth+1, embeddedOmit, expanding, fields)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+
+		tagged := name != ""
+		if !tagged {
+			name = f.Name
+		}
+		*fields = append(*fields, structField{name: name, opts: opts, value: fv, depth: depth, tagged: tagged, omit: omit})
+	}
+}
+
+func (c jsonConverter) time(t time.Time) interface{} {
+	switch c.timeFormat {
+	case "":
+		return t
+	case TimeFormatUnix:
+		return t.Unix()
+	case TimeFormatUnixMilli:
+		return t.UnixNano() / int64(time.Millisecond)
+	}
+	return t.Format(c.timeFormat)
+}
+
+// fieldSet is a tree of the fields to keep in objects, a field without
+// children being kept whole.
+type fieldSet map[string]fieldSet
+
+// newFieldSet builds a fieldSet from dotted paths.
+func newFieldSet(paths []string) fieldSet {
+	fs := fieldSet{}
+	for _, path := range paths {
+		names := strings.Split(strings.TrimSpace(path), ".")
+		node := fs
+		for i, name := range names {
+			if i == len(names)-1 {
+				node[name] = nil
+				break
+			}
+			child, ok := node[name]
+			if ok && child == nil {
+				// The parent is already kept whole.
+				break
+			}
+			if !ok {
+				child = fieldSet{}
+				node[name] = child
+			}
+			node = child
+		}
+	}
+	return fs
+}
+
+// filter drops the fields of the objects within v, as converted by a
+// jsonConverter, which aren't part of the set.
+func (fs fieldSet) filter(v interface{}) interface{} {
+	switch v := v.(type) {
+	case jsonObject:
+		obj := make(jsonObject, 0, len(fs))
+		for _, f := range v {
+			if child, ok := fs[f.name]; ok {
+				obj = append(obj, jsonField{name: f.name, value: child.keep(f.value)})
+			}
+		}
+		return obj
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(fs))
+		for name, child := range fs {
+			if value, ok := v[name]; ok {
+				m[name] = child.keep(value)
+			}
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, e := range v {
+			s[i] = fs.filter(e)
+		}
+		return s
+	}
+	return v
+}
+
+// keep returns v whole for a nil set, filtered otherwise.
+func (fs fieldSet) keep(v interface{}) interface{} {
+	if fs == nil {
+		return v
+	}
+	return fs.filter(v)
+}
+
+// mapKey converts a map key to a string the way encoding/json does.
+func mapKey(key reflect.Value) string {
+	if key.Kind() == reflect.String {
+		return key.String()
+	}
+	if tm, ok := key.Interface().(encoding.TextMarshaler); ok {
+		if b, err := tm.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10)
+	}
+	return fmt.Sprint(key.Interface())
+}
+
+func hasOption(opts, option string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptyValue mirrors the omitempty rules of encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
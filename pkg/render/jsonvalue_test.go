@@ -0,0 +1,274 @@
+package render
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type embeddedFields struct {
+	A int
+	a int
+}
+
+type embeddingValue struct {
+	embeddedFields
+	B int
+}
+
+type embeddingPointer struct {
+	*embeddedFields
+	B int
+}
+
+func TestJSONConverter_embedded(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		value interface{}
+	}{
+		{
+			desc:  "unexported embedded struct",
+			value: embeddingValue{embeddedFields: embeddedFields{A: 1}, B: 2},
+		},
+		{
+			desc:  "unexported embedded pointer",
+			value: embeddingPointer{embeddedFields: &embeddedFields{A: 1}, B: 2},
+		},
+		{
+			desc:  "nil unexported embedded pointer",
+			value: embeddingPointer{B: 2},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			expected, err := json.Marshal(test.value)
+			require.NoError(t, err)
+
+			converted, err := jsonConverter{tagName: "json"}.convert(test.value)
+			require.NoError(t, err)
+			actual, err := json.Marshal(converted)
+			require.NoError(t, err)
+
+			assert.Equal(t, string(expected), string(actual))
+		})
+	}
+}
+
+type pointerMarshaler struct {
+	Value int
+}
+
+func (p *pointerMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`"marshaled"`), nil
+}
+
+type pointerTextMarshaler struct {
+	Value int
+}
+
+func (p *pointerTextMarshaler) MarshalText() ([]byte, error) {
+	return []byte("text"), nil
+}
+
+type pointerMarshalers struct {
+	JSON pointerMarshaler
+	Text pointerTextMarshaler
+}
+
+func TestJSONConverter_pointerMarshalers(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		value interface{}
+	}{
+		{
+			desc:  "not addressable",
+			value: pointerMarshalers{},
+		},
+		{
+			desc:  "struct fields through a pointer",
+			value: &pointerMarshalers{},
+		},
+		{
+			desc:  "slice elements",
+			value: []pointerMarshaler{{Value: 1}, {Value: 2}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			expected, err := json.Marshal(test.value)
+			require.NoError(t, err)
+
+			converted, err := jsonConverter{tagName: "json", timeFormat: TimeFormatUnix}.convert(test.value)
+			require.NoError(t, err)
+			actual, err := json.Marshal(converted)
+			require.NoError(t, err)
+
+			assert.Equal(t, string(expected), string(actual))
+		})
+	}
+}
+
+type collidingA struct {
+	Name string
+	A    int
+}
+
+type collidingB struct {
+	Name string
+	B    int
+}
+
+type collidingTagged struct {
+	Title string `json:"Name"`
+}
+
+type collidingDeep struct {
+	collidingA
+}
+
+type collidingSame struct {
+	collidingA
+	collidingB
+}
+
+type collidingTaggedWins struct {
+	collidingA
+	collidingTagged
+}
+
+type collidingShallowWins struct {
+	collidingDeep
+	collidingB
+}
+
+type collidingNilPointer struct {
+	*collidingA
+	collidingB
+}
+
+type collidingOmitted struct {
+	Name string `json:",omitempty"`
+	collidingA
+}
+
+type recursiveEmbedding struct {
+	*recursiveEmbedding
+	Name string
+}
+
+func TestJSONConverter_collidingFields(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		value interface{}
+	}{
+		{
+			desc:  "same depth",
+			value: collidingSame{collidingA: collidingA{Name: "a", A: 1}, collidingB: collidingB{Name: "b", B: 2}},
+		},
+		{
+			desc:  "tagged field",
+			value: collidingTaggedWins{collidingA: collidingA{Name: "a"}, collidingTagged: collidingTagged{Title: "t"}},
+		},
+		{
+			desc:  "shallower field",
+			value: collidingShallowWins{collidingDeep: collidingDeep{collidingA{Name: "a"}}, collidingB: collidingB{Name: "b"}},
+		},
+		{
+			desc:  "nil embedded pointer",
+			value: collidingNilPointer{collidingB: collidingB{Name: "b"}},
+		},
+		{
+			desc:  "omitted field",
+			value: collidingOmitted{collidingA: collidingA{Name: "a"}},
+		},
+		{
+			desc:  "recursive embedding",
+			value: recursiveEmbedding{recursiveEmbedding: &recursiveEmbedding{Name: "inner"}, Name: "outer"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			expected, err := json.Marshal(test.value)
+			require.NoError(t, err)
+
+			converted, err := jsonConverter{tagName: "json"}.convert(test.value)
+			require.NoError(t, err)
+			actual, err := json.Marshal(converted)
+			require.NoError(t, err)
+
+			assert.Equal(t, string(expected), string(actual))
+		})
+	}
+}
+
+type cyclicNode struct {
+	Name string
+	Next *cyclicNode
+}
+
+func TestJSONConverter_cycle(t *testing.T) {
+	node := &cyclicNode{Name: "a"}
+	node.Next = node
+
+	m := map[string]interface{}{}
+	m["self"] = m
+
+	s := []interface{}{nil}
+	s[0] = s
+
+	shared := &cyclicNode{Name: "shared"}
+
+	testCases := []struct {
+		desc  string
+		value interface{}
+		cycle bool
+	}{
+		{
+			desc:  "pointer cycle",
+			value: node,
+			cycle: true,
+		},
+		{
+			desc:  "map cycle",
+			value: m,
+			cycle: true,
+		},
+		{
+			desc:  "slice cycle",
+			value: s,
+			cycle: true,
+		},
+		{
+			desc:  "shared pointer",
+			value: []*cyclicNode{shared, shared},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := jsonConverter{tagName: "json"}.convert(test.value)
+			if test.cycle {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// Markdown built-in renderer, converting markdown to HTML with RenderFunc, e.g.
+// blackfriday.Run. The HTML is always sanitized, with SanitizeFunc or a built-in
+// allowlist sanitizer when it's nil, so that untrusted markdown can't inject
+// scripts. When Layout is set, the sanitized HTML is rendered through it as the
+// template.HTML binding.
+type Markdown struct {
+	Head
+	RenderFunc   func([]byte) ([]byte, error)
+	SanitizeFunc func([]byte) ([]byte, error)
+	Layout       *HTML
+}
+
+// Render a markdown response.
+func (m Markdown) Render(w io.Writer, v interface{}) error {
+	var src []byte
+	switch s := v.(type) {
+	case string:
+		src = []byte(s)
+	case []byte:
+		src = s
+	default:
+		return fmt.Errorf("render: Markdown expects string or []byte, got %T", v)
+	}
+	if m.RenderFunc == nil {
+		return errors.New("render: Markdown has no RenderFunc")
+	}
+
+	result, err := m.RenderFunc(src)
+	if err != nil {
+		return err
+	}
+	if m.SanitizeFunc != nil {
+		result, err = m.SanitizeFunc(result)
+	} else {
+		result = sanitizeHTML(result)
+	}
+	if err != nil {
+		return err
+	}
+
+	if m.Layout != nil {
+		h := *m.Layout
+		h.Head = m.Head
+		return h.Render(w, template.HTML(result))
+	}
+
+	if hw, ok := w.(http.ResponseWriter); ok {
+		hw.Header().Set(ContentLength, strconv.Itoa(len(result)))
+		m.Head.setDefaultContentType(ContentHTML)
+		m.Head.Write(hw)
+	}
+	_, err = w.Write(result)
+	return err
+}
@@ -0,0 +1,177 @@
+package render
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// identityMarkdown renders markdown as is, leaving the sanitization to be tested.
+func identityMarkdown(src []byte) ([]byte, error) {
+	return src, nil
+}
+
+func TestSanitizeHTML(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		src      string
+		expected string
+	}{
+		{
+			desc:     "allowed tags",
+			src:      `<p><strong>a</strong> <a href="https://example.com" title="t">b</a></p>`,
+			expected: `<p><strong>a</strong> <a href="https://example.com" title="t">b</a></p>`,
+		},
+		{
+			desc:     "script",
+			src:      `<p>a</p><script>alert(1)</script><p>b</p>`,
+			expected: `<p>a</p><p>b</p>`,
+		},
+		{
+			desc:     "event handler",
+			src:      `<img src="https://example.com/a.png" onerror="alert(1)">`,
+			expected: `<img src="https://example.com/a.png">`,
+		},
+		{
+			desc:     "javascript URL",
+			src:      `<a href="javascript:alert(1)">a</a>`,
+			expected: `<a>a</a>`,
+		},
+		{
+			desc:     "unknown tag",
+			src:      `<div><span>a</span></div>`,
+			expected: `a`,
+		},
+		{
+			desc:     "comment",
+			src:      `a<!-- <script>alert(1)</script> -->b`,
+			expected: `ab`,
+		},
+		{
+			desc:     "stray brackets",
+			src:      `a < b > c`,
+			expected: `a &lt; b &gt; c`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, string(sanitizeHTML([]byte(test.src))))
+		})
+	}
+}
+
+func TestMarkdown_Render(t *testing.T) {
+	layout, err := template.New("page").Parse(`<main>{{ . }}</main>`)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		engine   Markdown
+		value    interface{}
+		expected string
+	}{
+		{
+			desc:     "string",
+			engine:   Markdown{RenderFunc: identityMarkdown},
+			value:    "<p>a</p><script>alert(1)</script>",
+			expected: "<p>a</p>",
+		},
+		{
+			desc:     "bytes",
+			engine:   Markdown{RenderFunc: identityMarkdown},
+			value:    []byte("<em>a</em>"),
+			expected: "<em>a</em>",
+		},
+		{
+			desc: "custom sanitizer",
+			engine: Markdown{
+				RenderFunc:   identityMarkdown,
+				SanitizeFunc: func(b []byte) ([]byte, error) { return append([]byte("<!-- safe -->"), b...), nil },
+			},
+			value:    "<div>a</div>",
+			expected: "<!-- safe --><div>a</div>",
+		},
+		{
+			desc:     "layout",
+			engine:   Markdown{RenderFunc: identityMarkdown, Layout: &HTML{Templates: layout, Name: "page"}},
+			value:    "<p>a</p><script>alert(1)</script>",
+			expected: "<main><p>a</p></main>",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			engine := test.engine
+			engine.Head.Status = http.StatusOK
+			recorder := httptest.NewRecorder()
+			err := engine.Render(recorder, test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, recorder.Body.String())
+			assert.Contains(t, recorder.Header().Get(ContentType), ContentHTML)
+		})
+	}
+}
+
+func TestMarkdown_Render_error(t *testing.T) {
+	failing := func([]byte) ([]byte, error) { return nil, errors.New("failed") }
+
+	testCases := []struct {
+		desc   string
+		engine Markdown
+		value  interface{}
+	}{
+		{
+			desc:   "nil",
+			engine: Markdown{RenderFunc: identityMarkdown},
+			value:  nil,
+		},
+		{
+			desc:   "not markdown",
+			engine: Markdown{RenderFunc: identityMarkdown},
+			value:  42,
+		},
+		{
+			desc:  "no RenderFunc",
+			value: "a",
+		},
+		{
+			desc:   "failing RenderFunc",
+			engine: Markdown{RenderFunc: failing},
+			value:  "a",
+		},
+		{
+			desc:   "failing SanitizeFunc",
+			engine: Markdown{RenderFunc: identityMarkdown, SanitizeFunc: failing},
+			value:  "a",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			engine := test.engine
+			engine.Head.Status = http.StatusOK
+			recorder := httptest.NewRecorder()
+			err := engine.Render(recorder, test.value)
+			require.Error(t, err)
+
+			assert.Empty(t, recorder.Header())
+			assert.Empty(t, recorder.Body.String())
+		})
+	}
+}
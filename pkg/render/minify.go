@@ -0,0 +1,126 @@
+package render
+
+import (
+	"bytes"
+)
+
+// rawTextElements are the elements whose content is kept as is when minifying.
+var rawTextElements = []string{"pre", "script", "style", "textarea"}
+
+// minifyHTML is the default HTML minifier. It removes comments, conditional
+// comments excepted, and collapses runs of whitespace into a single space,
+// leaving attribute values and the content of pre, script, style and textarea
+// elements untouched.
+func minifyHTML(src []byte) ([]byte, error) {
+	dst := make([]byte, 0, len(src))
+	space := false
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case isHTMLSpace(c):
+			space = true
+			i++
+			continue
+		case c == '<' && bytes.HasPrefix(src[i:], []byte("<!--")) && !bytes.HasPrefix(src[i:], []byte("<!--[if")):
+			end := bytes.Index(src[i+4:], []byte("-->"))
+			if end == -1 {
+				i = len(src)
+			} else {
+				i += 4 + end + 3
+			}
+			continue
+		}
+
+		if space {
+			if len(dst) > 0 {
+				dst = append(dst, ' ')
+			}
+			space = false
+		}
+
+		if c == '<' {
+			if name := rawTextElement(src[i+1:]); name != "" {
+				// Copy everything up to the closing tag of the element.
+				end := indexFold(src[i+1:], "</"+name)
+				if end == -1 {
+					return append(dst, src[i:]...), nil
+				}
+				dst = append(dst, src[i:i+1+end]...)
+				i += 1 + end
+				continue
+			}
+			if i+1 < len(src) && (isASCIILetter(src[i+1]) || src[i+1] == '/') {
+				var n int
+				dst, n = minifyTag(dst, src[i:])
+				i += n
+				continue
+			}
+		}
+		dst = append(dst, c)
+		i++
+	}
+	return dst, nil
+}
+
+// minifyTag appends the tag starting at src to dst, collapsing the whitespace
+// between attributes but keeping quoted attribute values as is, and returns the
+// number of bytes of src the tag spans.
+func minifyTag(dst, src []byte) ([]byte, int) {
+	var quote byte
+	space := false
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case isHTMLSpace(c):
+			space = true
+			continue
+		case c == '"' || c == '\'':
+			quote = c
+		}
+
+		if space {
+			dst = append(dst, ' ')
+			space = false
+		}
+		dst = append(dst, c)
+		if quote == 0 && c == '>' {
+			return dst, i + 1
+		}
+	}
+	return dst, len(src)
+}
+
+// rawTextElement returns the name of the raw text element opened by the tag
+// starting at b, or an empty string.
+func rawTextElement(b []byte) string {
+	for _, name := range rawTextElements {
+		if len(b) > len(name) && bytes.EqualFold(b[:len(name)], []byte(name)) {
+			if c := b[len(name)]; c == '>' || c == '/' || isHTMLSpace(c) {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// indexFold is a case insensitive bytes.Index for an ASCII lower case sep.
+func indexFold(b []byte, sep string) int {
+	for i := 0; i+len(sep) <= len(b); i++ {
+		if bytes.EqualFold(b[i:i+len(sep)], []byte(sep)) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isASCIILetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
@@ -0,0 +1,64 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinifyHTML(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		src      string
+		expected string
+	}{
+		{
+			desc:     "whitespace between elements",
+			src:      "<ul>\n  <li>a</li>\n\n  <li>b  c</li>\n</ul>",
+			expected: "<ul> <li>a</li> <li>b c</li> </ul>",
+		},
+		{
+			desc:     "comments",
+			src:      "<p>a<!-- comment -->b</p><!--[if IE]><p>ie</p><![endif]-->",
+			expected: "<p>ab</p><!--[if IE]><p>ie</p><![endif]-->",
+		},
+		{
+			desc:     "attribute values",
+			src:      `<input  type="text"` + "\n" + `  value="a  b" title='x   y'  data-list="1` + "\n" + `2">`,
+			expected: `<input type="text" value="a  b" title='x   y' data-list="1` + "\n" + `2">`,
+		},
+		{
+			desc:     "quote in attribute value",
+			src:      `<a title="it's  here"  href="/">x   y</a>`,
+			expected: `<a title="it's  here" href="/">x y</a>`,
+		},
+		{
+			desc:     "closing tags",
+			src:      "<p>a</p  >  <b>c</b>",
+			expected: "<p>a</p > <b>c</b>",
+		},
+		{
+			desc:     "raw text elements",
+			src:      "<pre>a\n  b</pre>  <script>if (a  < b) {}</script>",
+			expected: "<pre>a\n  b</pre> <script>if (a  < b) {}</script>",
+		},
+		{
+			desc:     "less than in text",
+			src:      "<p>1  < 2</p>",
+			expected: "<p>1 < 2</p>",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := minifyHTML([]byte(test.src))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, string(result))
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tinylib/msgp/msgp"
+)
+
+// appendMsgPack appends the MessagePack encoding of v to b, through msgp for the
+// values it supports and by reflection otherwise.
+func appendMsgPack(b []byte, v interface{}) ([]byte, error) {
+	result, err := msgp.AppendIntf(b, v)
+	if _, ok := err.(*msgp.ErrUnsupportedType); !ok {
+		return result, err
+	}
+	return appendMsgPackValue(b, reflect.ValueOf(v))
+}
+
+// appendMsgPackValue encodes rv by reflection. Struct fields are encoded as map
+// entries named after the `msg` struct tag, as msgp code generation does, falling
+// back to the field name, and honor the "-" and omitempty options. Map entries
+// are sorted by their encoded key.
+func appendMsgPackValue(b []byte, rv reflect.Value) ([]byte, error) {
+	if !rv.IsValid() {
+		return msgp.AppendNil(b), nil
+	}
+	if rv.CanInterface() {
+		switch v := rv.Interface().(type) {
+		case msgp.Marshaler:
+			if rv.Kind() == reflect.Ptr && rv.IsNil() {
+				return msgp.AppendNil(b), nil
+			}
+			return v.MarshalMsg(b)
+		case msgp.Extension:
+			return msgp.AppendExtension(b, v)
+		case time.Time:
+			return msgp.AppendTime(b, v), nil
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return msgp.AppendNil(b), nil
+		}
+		return appendMsgPackValue(b, rv.Elem())
+	case reflect.Bool:
+		return msgp.AppendBool(b, rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return msgp.AppendInt64(b, rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return msgp.AppendUint64(b, rv.Uint()), nil
+	case reflect.Float32:
+		return msgp.AppendFloat32(b, float32(rv.Float())), nil
+	case reflect.Float64:
+		return msgp.AppendFloat64(b, rv.Float()), nil
+	case reflect.Complex64:
+		return msgp.AppendComplex64(b, complex64(rv.Complex())), nil
+	case reflect.Complex128:
+		return msgp.AppendComplex128(b, rv.Complex()), nil
+	case reflect.String:
+		return msgp.AppendString(b, rv.String()), nil
+	case reflect.Slice:
+		if rv.IsNil() {
+			return msgp.AppendNil(b), nil
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return msgp.AppendBytes(b, rv.Bytes()), nil
+		}
+		fallthrough
+	case reflect.Array:
+		b = msgp.AppendArrayHeader(b, uint32(rv.Len()))
+		for i := 0; i < rv.Len(); i++ {
+			var err error
+			if b, err = appendMsgPackValue(b, rv.Index(i)); err != nil {
+				return b, err
+			}
+		}
+		return b, nil
+	case reflect.Map:
+		if rv.IsNil() {
+			return msgp.AppendNil(b), nil
+		}
+		return appendMsgPackMap(b, rv)
+	case reflect.Struct:
+		return appendMsgPackStruct(b, rv)
+	}
+	return b, fmt.Errorf("render: MsgPack cannot encode %s", rv.Type())
+}
+
+// appendMsgPackMap encodes a map with its entries sorted by their encoded key.
+func appendMsgPackMap(b []byte, rv reflect.Value) ([]byte, error) {
+	type entry struct {
+		key   []byte
+		value reflect.Value
+	}
+
+	entries := make([]entry, 0, rv.Len())
+	for _, k := range rv.MapKeys() {
+		key, err := appendMsgPackValue(nil, k)
+		if err != nil {
+			return b, err
+		}
+		entries = append(entries, entry{key: key, value: rv.MapIndex(k)})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+
+	b = msgp.AppendMapHeader(b, uint32(len(entries)))
+	for _, en := range entries {
+		b = append(b, en.key...)
+		var err error
+		if b, err = appendMsgPackValue(b, en.value); err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
+
+// appendMsgPackStruct encodes a struct as a map of its exported fields, in field order.
+func appendMsgPackStruct(b []byte, rv reflect.Value) ([]byte, error) {
+	var names []string
+	var values []reflect.Value
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name, opts := f.Name, ""
+		if tag := f.Tag.Get("msg"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if idx := strings.Index(tag, ","); idx != -1 {
+				tag, opts = tag[:idx], tag[idx+1:]
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+
+		fv := rv.Field(i)
+		if hasOption(opts, "omitempty") && isEmptyValue(fv) {
+			continue
+		}
+		names = append(names, name)
+		values = append(values, fv)
+	}
+
+	b = msgp.AppendMapHeader(b, uint32(len(names)))
+	for i, name := range names {
+		b = msgp.AppendString(b, name)
+		var err error
+		if b, err = appendMsgPackValue(b, values[i]); err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
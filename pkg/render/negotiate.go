@@ -0,0 +1,106 @@
+package render
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrNotAcceptable is returned by Negotiate when none of the offered media types
+// is acceptable to the client.
+var ErrNotAcceptable = errors.New("render: no acceptable media type")
+
+// acceptRange is a single media range of an Accept header.
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// Negotiate picks the engine whose media type best matches the request's Accept
+// header and renders v with it. Offers are keyed by media type, e.g. "application/json",
+// nil engines being ignored.
+// When nothing matches, a 406 Not Acceptable listing the offered types is written
+// and ErrNotAcceptable is returned. Vary: Accept is added to the response either way.
+func Negotiate(w http.ResponseWriter, r *http.Request, offers map[string]Engine, v interface{}) error {
+	types := make([]string, 0, len(offers))
+	for t, e := range offers {
+		if e != nil {
+			types = append(types, t)
+		}
+	}
+	sort.Strings(types)
+
+	addVary(w.Header(), "Accept")
+	mediaType := negotiateMediaType(r.Header.Get("Accept"), types)
+	if mediaType == "" {
+		http.Error(w, "Not Acceptable, available types: "+strings.Join(types, ", "), http.StatusNotAcceptable)
+		return ErrNotAcceptable
+	}
+
+	return offers[mediaType].Render(w, v)
+}
+
+// negotiateMediaType returns the offer preferred by the given Accept header value,
+// or an empty string if none is acceptable. Ties go to the first offer.
+func negotiateMediaType(accept string, offers []string) string {
+	ranges := parseAccept(accept)
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		q, specificity := 0.0, -1
+		for _, ar := range ranges {
+			s := mediaRangeMatch(ar.mediaType, offer)
+			// The most specific matching range sets the quality of an offer.
+			if s > specificity {
+				q, specificity = ar.q, s
+			}
+		}
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+// parseAccept parses an Accept header value, an empty value accepts anything.
+func parseAccept(accept string) []acceptRange {
+	if strings.TrimSpace(accept) == "" {
+		return []acceptRange{{mediaType: "*/*", q: 1}}
+	}
+
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		ar := acceptRange{mediaType: strings.ToLower(strings.TrimSpace(params[0])), q: 1}
+		if ar.mediaType == "" {
+			continue
+		}
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					ar.q = q
+				}
+			}
+		}
+		ranges = append(ranges, ar)
+	}
+	return ranges
+}
+
+// mediaRangeMatch returns how specifically the media range matches the media type:
+// -1 for no match, 0 for */*, 1 for type/* and 2 for an exact match.
+func mediaRangeMatch(mediaRange, mediaType string) int {
+	mediaType = strings.ToLower(mediaType)
+	switch {
+	case mediaRange == mediaType:
+		return 2
+	case mediaRange == "*/*":
+		return 0
+	case strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*")):
+		return 1
+	}
+	return -1
+}
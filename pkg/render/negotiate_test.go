@@ -0,0 +1,160 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateMediaType(t *testing.T) {
+	offers := []string{"application/json", "application/xml", "text/plain"}
+
+	testCases := []struct {
+		desc     string
+		accept   string
+		expected string
+	}{
+		{
+			desc:     "empty",
+			accept:   "",
+			expected: "application/json",
+		},
+		{
+			desc:     "exact match",
+			accept:   "text/plain",
+			expected: "text/plain",
+		},
+		{
+			desc:     "case insensitive",
+			accept:   "Application/XML",
+			expected: "application/xml",
+		},
+		{
+			desc:     "quality",
+			accept:   "application/json;q=0.5, application/xml",
+			expected: "application/xml",
+		},
+		{
+			desc:     "type wildcard",
+			accept:   "text/*",
+			expected: "text/plain",
+		},
+		{
+			desc:     "more specific range wins",
+			accept:   "*/*;q=0.8, application/json;q=0.1",
+			expected: "application/xml",
+		},
+		{
+			desc:     "refused type",
+			accept:   "application/json;q=0",
+			expected: "",
+		},
+		{
+			desc:     "no match",
+			accept:   "image/png",
+			expected: "",
+		},
+		{
+			desc:     "invalid quality",
+			accept:   "text/plain;q=abc",
+			expected: "text/plain",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, negotiateMediaType(test.accept, offers))
+		})
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	offers := map[string]Engine{
+		"application/json": JSON{Head: Head{Status: http.StatusOK}},
+		"text/plain":       Text{Head: Head{Status: http.StatusOK}},
+		"application/xml":  nil,
+	}
+
+	testCases := []struct {
+		desc           string
+		offers         map[string]Engine
+		accept         string
+		value          interface{}
+		expectedStatus int
+		expectedBody   string
+		expectedErr    error
+	}{
+		{
+			desc:           "JSON",
+			offers:         offers,
+			accept:         "application/json",
+			value:          "hello",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"hello"`,
+		},
+		{
+			desc:           "text",
+			offers:         offers,
+			accept:         "text/*",
+			value:          "hello",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "hello",
+		},
+		{
+			desc:           "nil engine",
+			offers:         offers,
+			accept:         "application/xml",
+			value:          "hello",
+			expectedStatus: http.StatusNotAcceptable,
+			expectedBody:   "Not Acceptable, available types: application/json, text/plain\n",
+			expectedErr:    ErrNotAcceptable,
+		},
+		{
+			desc:           "nil offers",
+			accept:         "application/json",
+			value:          "hello",
+			expectedStatus: http.StatusNotAcceptable,
+			expectedBody:   "Not Acceptable, available types: \n",
+			expectedErr:    ErrNotAcceptable,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept", test.accept)
+			recorder := httptest.NewRecorder()
+
+			err := Negotiate(recorder, req, test.offers, test.value)
+			if test.expectedErr != nil {
+				assert.Equal(t, test.expectedErr, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+			assert.Equal(t, "Accept", recorder.Header().Get(Vary))
+		})
+	}
+}
+
+func TestNegotiate_renderError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	recorder := httptest.NewRecorder()
+
+	err := Negotiate(recorder, req, map[string]Engine{"application/json": JSON{Head: Head{Status: http.StatusOK}}}, make(chan int))
+	require.Error(t, err)
+
+	assert.NotEqual(t, ErrNotAcceptable, err)
+	assert.Empty(t, recorder.Body.String())
+}
@@ -0,0 +1,424 @@
+package render
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// Plist built-in renderer for Apple property lists, in the XML format or in the
+// binary one when Binary is set.
+//
+// Struct fields are encoded as dictionary entries named after the `plist` struct
+// tag, falling back to the field name, and honor the "-" and omitempty options.
+// Maps must have string keys and are encoded in sorted key order. Property lists
+// have no null, so nil values are left out of dictionaries and arrays.
+type Plist struct {
+	Head
+	Binary bool
+}
+
+// plistDict is a property list dictionary keeping the order of its entries.
+type plistDict struct {
+	keys   []string
+	values []interface{}
+}
+
+// Render a property list response.
+func (p Plist) Render(w io.Writer, v interface{}) error {
+	v = p.Head.errorPayload(v)
+
+	value, err := plistValue(reflect.ValueOf(v))
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		return errors.New("render: Plist cannot encode a nil value")
+	}
+
+	// Retrieve a buffer from the pool to encode into.
+	buf := bufPool.Get()
+	defer bufPool.Put(buf)
+
+	if p.Binary {
+		writeBinaryPlist(buf, value)
+	} else {
+		buf.WriteString(xml.Header)
+		buf.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+		buf.WriteString(`<plist version="1.0">` + "\n")
+		writeXMLPlist(buf, value, 0)
+		buf.WriteString("</plist>\n")
+	}
+
+	// Property list encoded fine, write out the result.
+	if hw, ok := w.(http.ResponseWriter); ok {
+		if p.Head.ContentType == "" {
+			p.Head.ContentType = ContentPlist
+		}
+		hw.Header().Set(ContentLength, strconv.Itoa(buf.Len()))
+		p.Head.Write(hw)
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// plistValue converts rv into a bool, int64, uint64, float64, string, []byte,
+// time.Time, []interface{} or *plistDict, or nil for nil values.
+func plistValue(rv reflect.Value) (interface{}, error) {
+	if !rv.IsValid() {
+		return nil, nil
+	}
+	if rv.Type() == timeType {
+		return rv.Interface().(time.Time), nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return plistValue(rv.Elem())
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Slice:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Bytes(), nil
+		}
+		fallthrough
+	case reflect.Array:
+		values := make([]interface{}, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			value, err := plistValue(rv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			if value != nil {
+				values = append(values, value)
+			}
+		}
+		return values, nil
+	case reflect.Map:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("render: Plist cannot encode %s, keys must be strings", rv.Type())
+		}
+		keys := make([]string, 0, rv.Len())
+		for _, k := range rv.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+
+		dict := &plistDict{}
+		for _, k := range keys {
+			value, err := plistValue(rv.MapIndex(reflect.ValueOf(k).Convert(rv.Type().Key())))
+			if err != nil {
+				return nil, err
+			}
+			if value != nil {
+				dict.keys = append(dict.keys, k)
+				dict.values = append(dict.values, value)
+			}
+		}
+		return dict, nil
+	case reflect.Struct:
+		return plistStruct(rv)
+	}
+	return nil, fmt.Errorf("render: Plist cannot encode %s", rv.Type())
+}
+
+// plistStruct converts a struct into a dictionary of its exported fields, in field order.
+func plistStruct(rv reflect.Value) (*plistDict, error) {
+	dict := &plistDict{}
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name, opts := f.Name, ""
+		if tag := f.Tag.Get("plist"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if idx := strings.Index(tag, ","); idx != -1 {
+				tag, opts = tag[:idx], tag[idx+1:]
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+
+		fv := rv.Field(i)
+		if hasOption(opts, "omitempty") && isEmptyValue(fv) {
+			continue
+		}
+		value, err := plistValue(fv)
+		if err != nil {
+			return nil, err
+		}
+		if value != nil {
+			dict.keys = append(dict.keys, name)
+			dict.values = append(dict.values, value)
+		}
+	}
+	return dict, nil
+}
+
+// writeXMLPlist writes value as an XML property list element, indented with tabs.
+func writeXMLPlist(buf *bytes.Buffer, value interface{}, depth int) {
+	indent := strings.Repeat("\t", depth)
+	buf.WriteString(indent)
+
+	switch v := value.(type) {
+	case bool:
+		if v {
+			buf.WriteString("<true/>\n")
+		} else {
+			buf.WriteString("<false/>\n")
+		}
+	case int64:
+		buf.WriteString("<integer>" + strconv.FormatInt(v, 10) + "</integer>\n")
+	case uint64:
+		buf.WriteString("<integer>" + strconv.FormatUint(v, 10) + "</integer>\n")
+	case float64:
+		buf.WriteString("<real>" + plistReal(v) + "</real>\n")
+	case string:
+		buf.WriteString("<string>")
+		xml.EscapeText(buf, []byte(v))
+		buf.WriteString("</string>\n")
+	case []byte:
+		buf.WriteString("<data>" + base64.StdEncoding.EncodeToString(v) + "</data>\n")
+	case time.Time:
+		buf.WriteString("<date>" + v.UTC().Format(time.RFC3339) + "</date>\n")
+	case []interface{}:
+		if len(v) == 0 {
+			buf.WriteString("<array/>\n")
+			return
+		}
+		buf.WriteString("<array>\n")
+		for _, e := range v {
+			writeXMLPlist(buf, e, depth+1)
+		}
+		buf.WriteString(indent + "</array>\n")
+	case *plistDict:
+		if len(v.keys) == 0 {
+			buf.WriteString("<dict/>\n")
+			return
+		}
+		buf.WriteString("<dict>\n")
+		for i, k := range v.keys {
+			buf.WriteString(indent + "\t<key>")
+			xml.EscapeText(buf, []byte(k))
+			buf.WriteString("</key>\n")
+			writeXMLPlist(buf, v.values[i], depth+1)
+		}
+		buf.WriteString(indent + "</dict>\n")
+	}
+}
+
+// plistReal formats f the way CoreFoundation does, including infinities and NaN.
+func plistReal(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+infinity"
+	case math.IsInf(f, -1):
+		return "-infinity"
+	case math.IsNaN(f):
+		return "nan"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// plistEpoch is the reference date of binary property list dates.
+var plistEpoch = time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// plistRefs is an array or dictionary flattened into references to other objects,
+// the keys of a dictionary coming first.
+type plistRefs struct {
+	marker byte
+	count  int
+	refs   []int
+}
+
+// writeBinaryPlist writes value as a "bplist00" binary property list.
+func writeBinaryPlist(buf *bytes.Buffer, value interface{}) {
+	// Flatten the tree into a list of objects, collections referencing their
+	// members by index.
+	var objects []interface{}
+	var flatten func(v interface{}) int
+	flatten = func(v interface{}) int {
+		idx := len(objects)
+		objects = append(objects, nil)
+		switch v := v.(type) {
+		case []interface{}:
+			refs := plistRefs{marker: 0xa0, count: len(v)}
+			for _, e := range v {
+				refs.refs = append(refs.refs, flatten(e))
+			}
+			objects[idx] = refs
+		case *plistDict:
+			refs := plistRefs{marker: 0xd0, count: len(v.keys)}
+			for _, k := range v.keys {
+				refs.refs = append(refs.refs, flatten(k))
+			}
+			for _, e := range v.values {
+				refs.refs = append(refs.refs, flatten(e))
+			}
+			objects[idx] = refs
+		default:
+			objects[idx] = v
+		}
+		return idx
+	}
+	flatten(value)
+
+	refSize := plistIntSize(uint64(len(objects)))
+	start := buf.Len()
+	buf.WriteString("bplist00")
+
+	offsets := make([]uint64, len(objects))
+	for i, obj := range objects {
+		offsets[i] = uint64(buf.Len() - start)
+		switch v := obj.(type) {
+		case bool:
+			if v {
+				buf.WriteByte(0x09)
+			} else {
+				buf.WriteByte(0x08)
+			}
+		case int64:
+			if v < 0 {
+				buf.WriteByte(0x13)
+				writeSizedInt(buf, uint64(v), 8)
+			} else {
+				writeBinaryPlistInt(buf, uint64(v))
+			}
+		case uint64:
+			if v > math.MaxInt64 {
+				// Unsigned values beyond int64 take a 16 bytes integer.
+				buf.WriteByte(0x14)
+				writeSizedInt(buf, 0, 8)
+				writeSizedInt(buf, v, 8)
+			} else {
+				writeBinaryPlistInt(buf, v)
+			}
+		case float64:
+			buf.WriteByte(0x23)
+			writeSizedInt(buf, math.Float64bits(v), 8)
+		case time.Time:
+			buf.WriteByte(0x33)
+			writeSizedInt(buf, math.Float64bits(v.Sub(plistEpoch).Seconds()), 8)
+		case []byte:
+			writeBinaryPlistCount(buf, 0x40, len(v))
+			buf.Write(v)
+		case string:
+			if isASCII(v) {
+				writeBinaryPlistCount(buf, 0x50, len(v))
+				buf.WriteString(v)
+				continue
+			}
+			units := utf16.Encode([]rune(v))
+			writeBinaryPlistCount(buf, 0x60, len(units))
+			for _, u := range units {
+				writeSizedInt(buf, uint64(u), 2)
+			}
+		case plistRefs:
+			writeBinaryPlistCount(buf, v.marker, v.count)
+			for _, ref := range v.refs {
+				writeSizedInt(buf, uint64(ref), refSize)
+			}
+		}
+	}
+
+	offsetTable := uint64(buf.Len() - start)
+	offsetSize := plistIntSize(offsetTable)
+	for _, offset := range offsets {
+		writeSizedInt(buf, offset, offsetSize)
+	}
+
+	// The trailer: 6 unused bytes, the sizes of offsets and references, the
+	// number of objects, the index of the top object and the offset table offset.
+	buf.Write(make([]byte, 6))
+	buf.WriteByte(byte(offsetSize))
+	buf.WriteByte(byte(refSize))
+	writeSizedInt(buf, uint64(len(objects)), 8)
+	writeSizedInt(buf, 0, 8)
+	writeSizedInt(buf, offsetTable, 8)
+}
+
+// writeBinaryPlistInt writes n as an integer object of the smallest size.
+func writeBinaryPlistInt(buf *bytes.Buffer, n uint64) {
+	size := plistIntSize(n)
+	var exp byte
+	for s := size; s > 1; s >>= 1 {
+		exp++
+	}
+	buf.WriteByte(0x10 | exp)
+	writeSizedInt(buf, n, size)
+}
+
+// writeBinaryPlistCount writes an object marker along with a count, the count
+// following as an integer object when it doesn't fit in the marker.
+func writeBinaryPlistCount(buf *bytes.Buffer, marker byte, count int) {
+	if count < 15 {
+		buf.WriteByte(marker | byte(count))
+		return
+	}
+	buf.WriteByte(marker | 0x0f)
+	writeBinaryPlistInt(buf, uint64(count))
+}
+
+// plistIntSize returns the number of bytes, 1, 2, 4 or 8, needed to store n.
+func plistIntSize(n uint64) int {
+	switch {
+	case n <= math.MaxUint8:
+		return 1
+	case n <= math.MaxUint16:
+		return 2
+	case n <= math.MaxUint32:
+		return 4
+	}
+	return 8
+}
+
+// writeSizedInt writes the size low order bytes of n in big endian order.
+func writeSizedInt(buf *bytes.Buffer, n uint64, size int) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], n)
+	buf.Write(b[8-size:])
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+package render
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlist_Render(t *testing.T) {
+	type app struct {
+		Name    string            `plist:"CFBundleName"`
+		Version int               `plist:"version"`
+		Beta    bool              `plist:"beta,omitempty"`
+		Secret  string            `plist:"-"`
+		Icon    []byte            `plist:"icon"`
+		Tags    []interface{}     `plist:"tags"`
+		Extra   map[string]string `plist:"extra"`
+		Built   time.Time         `plist:"built"`
+		Parent  *app              `plist:"parent"`
+	}
+
+	value := app{
+		Name:    "a & b",
+		Version: 2,
+		Secret:  "s",
+		Icon:    []byte("png"),
+		Tags:    []interface{}{"x", nil, 1.5},
+		Extra:   map[string]string{"b": "2", "a": "1"},
+		Built:   time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CFBundleName</key>
+	<string>a &amp; b</string>
+	<key>version</key>
+	<integer>2</integer>
+	<key>icon</key>
+	<data>cG5n</data>
+	<key>tags</key>
+	<array>
+		<string>x</string>
+		<real>1.5</real>
+	</array>
+	<key>extra</key>
+	<dict>
+		<key>a</key>
+		<string>1</string>
+		<key>b</key>
+		<string>2</string>
+	</dict>
+	<key>built</key>
+	<date>2019-01-02T03:04:05Z</date>
+</dict>
+</plist>
+`
+
+	recorder := httptest.NewRecorder()
+	err := Plist{Head: Head{Status: http.StatusOK}}.Render(recorder, &value)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, recorder.Body.String())
+	assert.Equal(t, ContentPlist, recorder.Header().Get(ContentType))
+}
+
+func TestPlist_Render_binary(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := Plist{Binary: true}.Render(buf, true)
+	require.NoError(t, err)
+
+	expected := []byte("bplist00")
+	expected = append(expected, 0x09, 0x08)
+	expected = append(expected, 0, 0, 0, 0, 0, 0, 1, 1)
+	expected = append(expected, 0, 0, 0, 0, 0, 0, 0, 1)
+	expected = append(expected, 0, 0, 0, 0, 0, 0, 0, 0)
+	expected = append(expected, 0, 0, 0, 0, 0, 0, 0, 9)
+	assert.Equal(t, expected, buf.Bytes())
+}
+
+func TestPlist_Render_invalidValue(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		value interface{}
+	}{
+		{
+			desc:  "nil",
+			value: nil,
+		},
+		{
+			desc:  "nil pointer",
+			value: (*struct{ A int })(nil),
+		},
+		{
+			desc:  "integer keys",
+			value: map[int]string{1: "a"},
+		},
+		{
+			desc:  "channel",
+			value: make(chan int),
+		},
+		{
+			desc:  "func field",
+			value: struct{ F func() }{F: func() {}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		for _, binary := range []bool{false, true} {
+			binary := binary
+			name := test.desc
+			if binary {
+				name += " binary"
+			}
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				recorder := httptest.NewRecorder()
+				err := Plist{Head: Head{Status: http.StatusOK}, Binary: binary}.Render(recorder, test.value)
+				require.Error(t, err)
+
+				assert.Empty(t, recorder.Header())
+				assert.Empty(t, recorder.Body.String())
+			})
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ProblemDetails is an RFC 7807 problem details object.
+type ProblemDetails struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+// Problem built-in renderer for RFC 7807 error responses.
+type Problem struct {
+	Head
+	Indent bool
+}
+
+// Render a problem details response. The HTTP status defaults to the problem's
+// status when Head.Status is zero, and to 500 Internal Server Error when both are.
+func (p Problem) Render(w io.Writer, v interface{}) error {
+	var problem ProblemDetails
+	switch pd := v.(type) {
+	case ProblemDetails:
+		problem = pd
+	case *ProblemDetails:
+		if pd == nil {
+			return errors.New("render: Problem cannot render a nil *ProblemDetails")
+		}
+		problem = *pd
+	default:
+		return fmt.Errorf("render: Problem expects ProblemDetails, got %T", v)
+	}
+
+	if p.Head.Status == 0 {
+		p.Head.Status = problem.Status
+	}
+	if p.Head.Status == 0 {
+		p.Head.Status = http.StatusInternalServerError
+	}
+	p.Head.setDefaultContentType(ContentProblemJSON)
+
+	j := JSON{
+		Head:   p.Head,
+		Indent: p.Indent,
+	}
+
+	return j.Render(w, problem)
+}
@@ -0,0 +1,89 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblem_Render(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		engine         Problem
+		value          interface{}
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			desc:           "problem status",
+			value:          ProblemDetails{Title: "Not Found", Status: http.StatusNotFound},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"title":"Not Found","status":404}`,
+		},
+		{
+			desc:           "head status",
+			engine:         Problem{Head: Head{Status: http.StatusConflict}},
+			value:          &ProblemDetails{Title: "Conflict", Status: http.StatusBadRequest},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"title":"Conflict","status":400}`,
+		},
+		{
+			desc:           "no status",
+			value:          ProblemDetails{Title: "Oops"},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"title":"Oops"}`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			err := test.engine.Render(recorder, test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, ContentProblemJSON+"; charset=UTF-8", recorder.Header().Get(ContentType))
+			assert.JSONEq(t, test.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestProblem_Render_invalidValue(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		value interface{}
+	}{
+		{
+			desc:  "nil problem",
+			value: (*ProblemDetails)(nil),
+		},
+		{
+			desc:  "nil",
+			value: nil,
+		},
+		{
+			desc:  "other type",
+			value: "oops",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			err := Problem{}.Render(recorder, test.value)
+			require.Error(t, err)
+
+			assert.Empty(t, recorder.Header())
+			assert.Empty(t, recorder.Body.String())
+		})
+	}
+}
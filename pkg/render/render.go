@@ -0,0 +1,1113 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"image"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	// CacheControl header constant.
+	CacheControl = "Cache-Control"
+	// ContentAtom header value for Atom feeds.
+	ContentAtom = "application/atom+xml"
+	// ContentBinary header value for binary data.
+	ContentBinary = "application/octet-stream"
+	// ContentCalendar header value for iCalendar data.
+	ContentCalendar = "text/calendar"
+	// ContentCBOR header value for CBOR data.
+	ContentCBOR = "application/cbor"
+	// ContentCSV header value for CSV data.
+	ContentCSV = "text/csv"
+	// ContentDisposition header constant.
+	ContentDisposition = "Content-Disposition"
+	// ContentEncoding header constant.
+	ContentEncoding = "Content-Encoding"
+	// ContentEventStream header value for Server-Sent Events.
+	ContentEventStream = "text/event-stream"
+	// ContentForm header value for form-urlencoded data.
+	ContentForm = "application/x-www-form-urlencoded"
+	// ContentGeoJSON header value for GeoJSON data.
+	ContentGeoJSON = "application/geo+json"
+	// ContentGIF header value for GIF images.
+	ContentGIF = "image/gif"
+	// ContentHALJSON header value for HAL+JSON data.
+	ContentHALJSON = "application/hal+json"
+	// ContentHTML header value for HTML data.
+	ContentHTML = "text/html"
+	// ContentJPEG header value for JPEG images.
+	ContentJPEG = "image/jpeg"
+	// ContentJSON header value for JSON data.
+	ContentJSON = "application/json"
+	// ContentJSONAPI header value for JSON:API data.
+	ContentJSONAPI = "application/vnd.api+json"
+	// ContentJSONP header value for JSONP data.
+	ContentJSONP = "application/javascript"
+	// ContentLength header constant.
+	ContentLength = "Content-Length"
+	// ContentMsgPack header value for MessagePack data.
+	ContentMsgPack = "application/msgpack"
+	// ContentNDJSON header value for JSON Lines data.
+	ContentNDJSON = "application/x-ndjson"
+	// ContentPlist header value for Apple property lists.
+	ContentPlist = "application/x-plist"
+	// ContentPNG header value for PNG images.
+	ContentPNG = "image/png"
+	// ContentProblemJSON header value for RFC 7807 problem details.
+	ContentProblemJSON = "application/problem+json"
+	// ContentProtobuf header value for Protobuf data.
+	ContentProtobuf = "application/x-protobuf"
+	// ContentRSS header value for RSS feeds.
+	ContentRSS = "application/rss+xml"
+	// ContentSecurityPolicy header constant.
+	ContentSecurityPolicy = "Content-Security-Policy"
+	// ContentSitemap header value for sitemaps and sitemap indexes.
+	ContentSitemap = "application/xml"
+	// ContentSRT header value for SubRip subtitles.
+	ContentSRT = "application/x-subrip"
+	// ContentText header value for Text data.
+	ContentText = "text/plain"
+	// ContentTOML header value for TOML data.
+	ContentTOML = "application/toml"
+	// ContentType header constant.
+	ContentType = "Content-Type"
+	// ContentVCard header value for vCard contacts.
+	ContentVCard = "text/vcard"
+	// ContentVTT header value for WebVTT subtitles.
+	ContentVTT = "text/vtt"
+	// ContentXHTML header value for XHTML data.
+	ContentXHTML = "application/xhtml+xml"
+	// ContentXML header value for XML data.
+	ContentXML = "text/xml"
+	// ContentYAML header value for YAML data.
+	ContentYAML = "application/x-yaml"
+	// Expires header constant.
+	Expires = "Expires"
+	// LastModified header constant.
+	LastModified = "Last-Modified"
+	// SecureJSONPrefix is the anti JSON hijacking prefix popularized by Google and Angular.
+	SecureJSONPrefix = ")]}',\n"
+	// Vary header constant.
+	Vary = "Vary"
+	// Default character encoding.
+	defaultCharset = "UTF-8"
+	// UTF-8 byte order mark.
+	utf8BOM = "\xEF\xBB\xBF"
+)
+
+// helperFuncs had to be moved out. See helpers.go|helpers_pre16.go files.
+
+// Delims represents a set of Left and Right delimiters for HTML template rendering.
+type Delims struct {
+	// Left delimiter, defaults to {{.
+	Left string
+	// Right delimiter, defaults to }}.
+	Right string
+}
+
+// Options is a struct for specifying configuration options for the render.Render object.
+type Options struct {
+	// Directory to load templates. Default is "templates".
+	Directory string
+	// Asset function to use in place of directory. Defaults to nil.
+	Asset func(name string) ([]byte, error)
+	// AssetNames function to use in place of directory. Defaults to nil.
+	AssetNames func() []string
+	// Layout template name. Will not render a layout if blank (""). Defaults to blank ("").
+	Layout string
+	// Extensions to parse template files from. Defaults to [".tmpl"].
+	Extensions []string
+	// Funcs is a slice of FuncMaps to apply to the template upon compilation. This is useful for helper functions. Defaults to [].
+	Funcs []template.FuncMap
+	// Delims sets the action delimiters to the specified strings in the Delims struct.
+	Delims Delims
+	// Appends the given character set to the Content-Type header. Default is "UTF-8".
+	Charset string
+	// Outputs human readable JSON.
+	IndentJSON bool
+	// Outputs human readable XML. Default is false.
+	IndentXML bool
+	// Prefixes the JSON output with the given bytes. Default is false.
+	PrefixJSON []byte
+	// Prefixes the JSON output with SecureJSONPrefix, can't be combined with PrefixJSON. Default is false.
+	SecurePrefixJSON bool
+	// Prefixes the XML output with the given bytes.
+	PrefixXML []byte
+	// Writes the standard XML declaration before the XML output. Default is false.
+	HeaderXML bool
+	// Streams XML responses instead of marshalling prior to sending. Default is false.
+	StreamingXML bool
+	// Wraps the XML output in a root element with the given name, required to render slices and maps. Default is blank ("").
+	RootElementXML string
+	// Streams MessagePack responses instead of marshalling prior to sending. Default is false.
+	StreamingMsgPack bool
+	// Streams CBOR responses instead of encoding prior to sending. Default is false.
+	StreamingCBOR bool
+	// Marshals Protobuf messages deterministically so identical messages produce identical bytes. Default is false.
+	DeterministicProtobuf bool
+	// Indents nested TOML tables. Default is false.
+	IndentTOML bool
+	// Prefixes the TOML output with the given bytes, useful for leading comments.
+	PrefixTOML []byte
+	// Outputs YAML terminated by a new line. Default is false.
+	IndentYAML bool
+	// Prefixes the YAML output with the given bytes.
+	PrefixYAML []byte
+	// Allows changing of output to XHTML instead of HTML. Default is "text/html".
+	HTMLContentType string
+	// If IsDevelopment is set to true, this will recompile the templates on every request. Default is false.
+	IsDevelopment bool
+	// Unescape HTML characters "&<>" to their original values. Default is false.
+	UnEscapeHTML bool
+	// Streams JSON responses instead of marshalling prior to sending. Default is false.
+	StreamingJSON bool
+	// Formats time.Time values in JSON with the given layout, or as TimeFormatUnix/TimeFormatUnixMilli timestamps. Default is RFC 3339.
+	TimeFormatJSON string
+	// Reads JSON field names and options from the given struct tag instead of `json`. Default is blank ("").
+	TagNameJSON string
+	// Emits every JSON object, including struct fields, in sorted key order. This has a cost. Default is false.
+	SortJSONKeys bool
+	// Require that all partials executed in the layout are implemented in all templates using the layout. Default is false.
+	RequirePartials bool
+	// Deprecated: Use the above `RequirePartials` instead of this. As of Go 1.6, blocks are built in. Default is false.
+	RequireBlocks bool
+	// Sniffs the Content-Type of Data responses from their first 512 bytes instead of using "application/octet-stream". Default is false.
+	DetectDataContentType bool
+	// Disables automatic rendering of http.StatusInternalServerError when an error occurs. Default is false.
+	DisableHTTPErrorRendering bool
+	// Enables using partials without the current filename suffix which allows use of the same template in multiple files. e.g {{ partial "carosuel" }} inside the home template will match carosel-home or carosel.
+	RenderPartialsWithoutPrefix bool
+	// Called after every render with the engine name, e.g. "JSON", the time it took, the number of body bytes written and the error if any. Default is nil.
+	OnRender func(engine string, d time.Duration, n int, err error)
+}
+
+// HTMLOptions is a struct for overriding some rendering Options for specific HTML call.
+type HTMLOptions struct {
+	// Layout template name. Overrides Options.Layout.
+	Layout string
+}
+
+// Render is a service that provides functions for easily writing JSON, XML,
+// binary data, and HTML templates out to a HTTP Response.
+type Render struct {
+	// Customize Secure with an Options struct.
+	opt             Options
+	templates       *template.Template
+	templatesLk     sync.Mutex
+	compiledCharset string
+}
+
+// New constructs a new Render instance with the supplied options.
+func New(options ...Options) *Render {
+	var o Options
+	if len(options) == 0 {
+		o = Options{}
+	} else {
+		o = options[0]
+	}
+
+	r := Render{
+		opt: o,
+	}
+
+	r.prepareOptions()
+	r.compileTemplates()
+
+	return &r
+}
+
+func (r *Render) prepareOptions() {
+	// Fill in the defaults if need be.
+	if len(r.opt.Charset) == 0 {
+		r.opt.Charset = defaultCharset
+	}
+	r.compiledCharset = "; charset=" + r.opt.Charset
+
+	if len(r.opt.Directory) == 0 {
+		r.opt.Directory = "templates"
+	}
+	if len(r.opt.Extensions) == 0 {
+		r.opt.Extensions = []string{".tmpl"}
+	}
+	if len(r.opt.HTMLContentType) == 0 {
+		r.opt.HTMLContentType = ContentHTML
+	}
+}
+
+func (r *Render) compileTemplates() {
+	if r.opt.Asset == nil || r.opt.AssetNames == nil {
+		r.compileTemplatesFromDir()
+		return
+	}
+	r.compileTemplatesFromAsset()
+}
+
+func (r *Render) compileTemplatesFromDir() {
+	dir := r.opt.Directory
+	r.templates = template.New(dir)
+	r.templates.Delims(r.opt.Delims.Left, r.opt.Delims.Right)
+
+	// Walk the supplied directory and compile any files that match our extension list.
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		// Fix same-extension-dirs bug: some dir might be named to: "users.tmpl", "local.html".
+		// These dirs should be excluded as they are not valid golang templates, but files under
+		// them should be treat as normal.
+		// If is a dir, return immediately (dir is not a valid golang template).
+		if info == nil || info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		ext := ""
+		if strings.Index(rel, ".") != -1 {
+			ext = filepath.Ext(rel)
+		}
+
+		for _, extension := range r.opt.Extensions {
+			if ext == extension {
+				buf, err := ioutil.ReadFile(path)
+				if err != nil {
+					panic(err)
+				}
+
+				name := (rel[0 : len(rel)-len(ext)])
+				tmpl := r.templates.New(filepath.ToSlash(name))
+
+				// Add our funcmaps.
+				for _, funcs := range r.opt.Funcs {
+					tmpl.Funcs(funcs)
+				}
+
+				// Break out if this parsing fails. We don't want any silent server starts.
+				template.Must(tmpl.Funcs(helperFuncs).Parse(string(buf)))
+				break
+			}
+		}
+		return nil
+	})
+}
+
+func (r *Render) compileTemplatesFromAsset() {
+	dir := r.opt.Directory
+	r.templates = template.New(dir)
+	r.templates.Delims(r.opt.Delims.Left, r.opt.Delims.Right)
+
+	for _, path := range r.opt.AssetNames() {
+		if !strings.HasPrefix(path, dir) {
+			continue
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			panic(err)
+		}
+
+		ext := ""
+		if strings.Index(rel, ".") != -1 {
+			ext = "." + strings.Join(strings.Split(rel, ".")[1:], ".")
+		}
+
+		for _, extension := range r.opt.Extensions {
+			if ext == extension {
+
+				buf, err := r.opt.Asset(path)
+				if err != nil {
+					panic(err)
+				}
+
+				name := (rel[0 : len(rel)-len(ext)])
+				tmpl := r.templates.New(filepath.ToSlash(name))
+
+				// Add our funcmaps.
+				for _, funcs := range r.opt.Funcs {
+					tmpl.Funcs(funcs)
+				}
+
+				// Break out if this parsing fails. We don't want any silent server starts.
+				template.Must(tmpl.Funcs(helperFuncs).Parse(string(buf)))
+				break
+			}
+		}
+	}
+}
+
+// TemplateLookup is a wrapper around template.Lookup and returns
+// the template with the given name that is associated with t, or nil
+// if there is no such template.
+func (r *Render) TemplateLookup(t string) *template.Template {
+	return r.templates.Lookup(t)
+}
+
+func (r *Render) execute(name string, binding interface{}) (*bytes.Buffer, error) {
+	buf := new(bytes.Buffer)
+	return buf, r.templates.ExecuteTemplate(buf, name, binding)
+}
+
+func (r *Render) addLayoutFuncs(name string, binding interface{}) {
+	funcs := template.FuncMap{
+		"yield": func() (template.HTML, error) {
+			buf, err := r.execute(name, binding)
+			// Return safe HTML here since we are rendering our own template.
+			return template.HTML(buf.String()), err
+		},
+		"current": func() (string, error) {
+			return name, nil
+		},
+		"block": func(partialName string) (template.HTML, error) {
+			log.Print("Render's `block` implementation is now depericated. Use `partial` as a drop in replacement.")
+			fullPartialName := fmt.Sprintf("%s-%s", partialName, name)
+			if r.TemplateLookup(fullPartialName) == nil && r.opt.RenderPartialsWithoutPrefix {
+				fullPartialName = partialName
+			}
+			if r.opt.RequireBlocks || r.TemplateLookup(fullPartialName) != nil {
+				buf, err := r.execute(fullPartialName, binding)
+				// Return safe HTML here since we are rendering our own template.
+				return template.HTML(buf.String()), err
+			}
+			return "", nil
+		},
+		"partial": func(partialName string) (template.HTML, error) {
+			fullPartialName := fmt.Sprintf("%s-%s", partialName, name)
+			if r.TemplateLookup(fullPartialName) == nil && r.opt.RenderPartialsWithoutPrefix {
+				fullPartialName = partialName
+			}
+			if r.opt.RequirePartials || r.TemplateLookup(fullPartialName) != nil {
+				buf, err := r.execute(fullPartialName, binding)
+				// Return safe HTML here since we are rendering our own template.
+				return template.HTML(buf.String()), err
+			}
+			return "", nil
+		},
+	}
+	if tpl := r.templates.Lookup(name); tpl != nil {
+		tpl.Funcs(funcs)
+	}
+}
+
+func (r *Render) prepareHTMLOptions(htmlOpt []HTMLOptions) HTMLOptions {
+	if len(htmlOpt) > 0 {
+		return htmlOpt[0]
+	}
+
+	return HTMLOptions{
+		Layout: r.opt.Layout,
+	}
+}
+
+// Render is the generic function called by XML, JSON, Data, HTML, and can be called by custom implementations.
+func (r *Render) Render(w io.Writer, e Engine, data interface{}) error {
+	if r.opt.OnRender != nil {
+		return r.observe(w, e, func(w io.Writer) error {
+			return e.Render(w, data)
+		})
+	}
+	return r.renderError(w, e.Render(w, data))
+}
+
+// Atom writes out the given feed as an Atom feed.
+func (r *Render) Atom(w io.Writer, status int, v Feed) error {
+	head := Head{
+		ContentType: ContentAtom + r.compiledCharset,
+		Status:      status,
+	}
+
+	a := Atom{
+		Head:   head,
+		Indent: r.opt.IndentXML,
+	}
+
+	return r.Render(w, a, v)
+}
+
+// CBOR encodes the given interface object and writes the CBOR response.
+func (r *Render) CBOR(w io.Writer, status int, v interface{}) error {
+	head := Head{
+		ContentType: ContentCBOR,
+		Status:      status,
+	}
+
+	c := CBOR{
+		Head:      head,
+		Streaming: r.opt.StreamingCBOR,
+	}
+
+	return r.Render(w, c, v)
+}
+
+// CSV writes out the given records or slice of structs as CSV.
+func (r *Render) CSV(w io.Writer, status int, v interface{}) error {
+	head := Head{
+		ContentType: ContentCSV + r.compiledCharset,
+		Status:      status,
+	}
+
+	c := CSV{
+		Head:        head,
+		WriteHeader: true,
+	}
+
+	return r.Render(w, c, v)
+}
+
+// RenderRequest is like Render but aware of the request being answered. For HEAD
+// requests the headers, including Content-Length when known, are written without a body.
+// A nil request renders like Render.
+func (r *Render) RenderRequest(w http.ResponseWriter, req *http.Request, e Engine, data interface{}) error {
+	if req != nil && req.Method == http.MethodHead {
+		w = bodylessResponseWriter{ResponseWriter: w}
+	}
+	return r.Render(w, e, data)
+}
+
+// RenderPretty is like RenderRequest but indents the output of engines having an
+// Indent field when the request asks for it, with a "pretty" query parameter such
+// as ?pretty or ?pretty=1, or an "indent" parameter in its Accept header. The
+// engine is copied, never modified.
+func (r *Render) RenderPretty(w http.ResponseWriter, req *http.Request, e Engine, data interface{}) error {
+	if prettyRequested(req) {
+		e = withIndent(e)
+	}
+	return r.RenderRequest(w, req, e, data)
+}
+
+// prettyRequested reports whether the request asks for indented output.
+func prettyRequested(req *http.Request) bool {
+	if values, ok := req.URL.Query()["pretty"]; ok {
+		if values[0] == "" {
+			return true
+		}
+		pretty, err := strconv.ParseBool(values[0])
+		return err == nil && pretty
+	}
+
+	for _, part := range strings.Split(req.Header.Get("Accept"), ",") {
+		params := strings.Split(part, ";")
+		for _, param := range params[1:] {
+			name := strings.TrimSpace(param)
+			value := ""
+			if idx := strings.Index(name, "="); idx != -1 {
+				name, value = strings.TrimSpace(name[:idx]), strings.TrimSpace(name[idx+1:])
+			}
+			if strings.EqualFold(name, "indent") && value != "0" && !strings.EqualFold(value, "false") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// withIndent returns a copy of the engine with its Indent field set, or the
+// engine itself when it has no such field.
+func withIndent(e Engine) Engine {
+	rv := reflect.ValueOf(e)
+	if rv.Kind() != reflect.Struct {
+		return e
+	}
+	if f, ok := rv.Type().FieldByName("Indent"); !ok || f.Type.Kind() != reflect.Bool {
+		return e
+	}
+
+	cp := reflect.New(rv.Type()).Elem()
+	cp.Set(rv)
+	cp.FieldByName("Indent").SetBool(true)
+	return cp.Interface().(Engine)
+}
+
+// RenderBytes runs the given engine against v and returns the rendered output.
+// No head is written since the output never goes to an http.ResponseWriter.
+func RenderBytes(e Engine, v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := e.Render(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderN runs the given engine against v like Render and returns the number of
+// body bytes written to w, e.g. for response size metrics.
+func RenderN(w io.Writer, e Engine, v interface{}) (int, error) {
+	cw := newCountingWriter(w)
+	err := e.Render(cw, v)
+	return cw.count(), err
+}
+
+// RenderHead runs the given engine against v, writing the body to w even if it
+// isn't an http.ResponseWriter, and returns the head the engine resolved: the content type, status and headers
+// it would have written to an http.ResponseWriter. This lets callers writing to
+// files or object stores keep the metadata along with the body.
+func RenderHead(w io.Writer, e Engine, v interface{}) (Head, error) {
+	hw := newHeadResponseWriter(w)
+	if err := e.Render(hw, v); err != nil {
+		return Head{}, err
+	}
+	return Head{
+		ContentType: hw.header.Get(ContentType),
+		Status:      hw.status,
+		Headers:     hw.header,
+	}, nil
+}
+
+// RenderCtx is like Render but lets engines implementing ContextEngine observe ctx cancellation.
+func (r *Render) RenderCtx(ctx context.Context, w io.Writer, e Engine, data interface{}) error {
+	ce, ok := e.(ContextEngine)
+	if !ok {
+		return r.Render(w, e, data)
+	}
+
+	if r.opt.OnRender != nil {
+		return r.observe(w, e, func(w io.Writer) error {
+			return ce.RenderCtx(ctx, w, data)
+		})
+	}
+	return r.renderError(w, ce.RenderCtx(ctx, w, data))
+}
+
+// renderError writes an internal server error when err isn't nil, unless disabled.
+func (r *Render) renderError(w io.Writer, err error) error {
+	if hw, ok := w.(http.ResponseWriter); err != nil && !r.opt.DisableHTTPErrorRendering && ok {
+		http.Error(hw, err.Error(), http.StatusInternalServerError)
+	}
+	return err
+}
+
+// observe runs render and reports it to the OnRender hook.
+func (r *Render) observe(w io.Writer, e Engine, render func(io.Writer) error) error {
+	start := time.Now()
+	cw := newCountingWriter(w)
+	err := r.renderError(cw, render(cw))
+	r.opt.OnRender(engineName(e), time.Since(start), cw.count(), err)
+	return err
+}
+
+// engineName returns the type name of the engine, e.g. "JSON".
+func engineName(e Engine) string {
+	t := reflect.TypeOf(e)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// RenderStatus is like Render but writes the given status instead of the one
+// configured on the engine. The engine is copied, so that an engine shared
+// between goroutines never has its Head mutated. Statuses an engine computes
+// itself, such as a 304 or a 204, are kept.
+func (r *Render) RenderStatus(w io.Writer, e Engine, status int, data interface{}) error {
+	if se, ok := withStatus(e, status); ok {
+		return r.Render(w, se, data)
+	}
+	if hw, ok := w.(http.ResponseWriter); ok {
+		w = &statusResponseWriter{ResponseWriter: hw, status: status}
+	}
+	return r.Render(w, e, data)
+}
+
+// withStatus returns a copy of e writing status in place of the status of its
+// Head, looking through the wrapper engines. It reports false for the engines
+// without a Head.
+func withStatus(e Engine, status int) (Engine, bool) {
+	if c, ok := e.(Cached); ok && status != http.StatusOK {
+		// Only 200 responses are cached, render anew.
+		return withStatus(c.Engine, status)
+	}
+
+	rv := reflect.ValueOf(e)
+	ptr := rv.Kind() == reflect.Ptr
+	if ptr {
+		if rv.IsNil() {
+			return e, false
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return e, false
+	}
+
+	cp := reflect.New(rv.Type()).Elem()
+	cp.Set(rv)
+	if head := cp.FieldByName("Head"); head.IsValid() && head.Type() == reflect.TypeOf(Head{}) {
+		head.FieldByName("Status").SetInt(int64(status))
+	} else if inner := cp.FieldByName("Engine"); inner.IsValid() && inner.Type() == reflect.TypeOf((*Engine)(nil)).Elem() && !inner.IsNil() {
+		// A wrapper engine such as Gzip or ETag.
+		se, ok := withStatus(inner.Interface().(Engine), status)
+		if !ok {
+			return e, false
+		}
+		inner.Set(reflect.ValueOf(&se).Elem())
+	} else {
+		return e, false
+	}
+
+	if ptr {
+		return cp.Addr().Interface().(Engine), true
+	}
+	return cp.Interface().(Engine), true
+}
+
+// Data writes out the raw bytes as binary data.
+func (r *Render) Data(w io.Writer, status int, v []byte) error {
+	head := Head{
+		ContentType: ContentBinary,
+		Status:      status,
+	}
+	if r.opt.DetectDataContentType {
+		head.ContentType = ""
+	}
+
+	d := Data{
+		Head:              head,
+		DetectContentType: r.opt.DetectDataContentType,
+	}
+
+	return r.Render(w, d, v)
+}
+
+// DataStream copies the given reader out as binary data, flushing as it goes.
+// A positive length is sent as the Content-Length.
+func (r *Render) DataStream(w io.Writer, status int, v io.Reader, length int64) error {
+	head := Head{
+		ContentType: ContentBinary,
+		Status:      status,
+	}
+	if r.opt.DetectDataContentType {
+		head.ContentType = ""
+	}
+
+	d := Data{
+		Head:              head,
+		DetectContentType: r.opt.DetectDataContentType,
+		ContentLength:     length,
+	}
+
+	return r.Render(w, d, v)
+}
+
+// Form encodes the given values and writes the form-urlencoded response.
+func (r *Render) Form(w io.Writer, status int, v interface{}) error {
+	head := Head{
+		ContentType: ContentForm,
+		Status:      status,
+	}
+
+	f := Form{
+		Head:     head,
+		SortKeys: true,
+	}
+
+	return r.Render(w, f, v)
+}
+
+// GeoJSON marshals the given interface object and writes the GeoJSON response.
+func (r *Render) GeoJSON(w io.Writer, status int, v interface{}) error {
+	head := Head{
+		ContentType: ContentGeoJSON + r.compiledCharset,
+		Status:      status,
+	}
+
+	g := GeoJSON{
+		Head:   head,
+		Indent: r.opt.IndentJSON,
+	}
+
+	return r.Render(w, g, v)
+}
+
+// HAL marshals the given interface object and writes the HAL+JSON response.
+func (r *Render) HAL(w io.Writer, status int, v interface{}) error {
+	head := Head{
+		ContentType: ContentHALJSON + r.compiledCharset,
+		Status:      status,
+	}
+
+	h := HAL{
+		Head:   head,
+		Indent: r.opt.IndentJSON,
+	}
+
+	return r.Render(w, h, v)
+}
+
+// HTML builds up the response from the specified template and bindings.
+func (r *Render) HTML(w io.Writer, status int, name string, binding interface{}, htmlOpt ...HTMLOptions) error {
+	r.templatesLk.Lock()
+	defer r.templatesLk.Unlock()
+
+	// If we are in development mode, recompile the templates on every HTML request.
+	if r.opt.IsDevelopment {
+		r.compileTemplates()
+	}
+
+	opt := r.prepareHTMLOptions(htmlOpt)
+	// Assign a layout if there is one.
+	if len(opt.Layout) > 0 {
+		r.addLayoutFuncs(name, binding)
+		name = opt.Layout
+	}
+
+	head := Head{
+		ContentType: r.opt.HTMLContentType + r.compiledCharset,
+		Status:      status,
+	}
+
+	h := HTML{
+		Head:      head,
+		Name:      name,
+		Templates: r.templates,
+	}
+
+	return r.Render(w, h, binding)
+}
+
+// ICal writes out the given calendar as iCalendar data.
+func (r *Render) ICal(w io.Writer, status int, v Calendar) error {
+	head := Head{
+		ContentType: ContentCalendar + r.compiledCharset,
+		Status:      status,
+	}
+
+	i := ICal{
+		Head: head,
+	}
+
+	return r.Render(w, i, v)
+}
+
+// INI encodes the given struct or map and writes the INI response.
+func (r *Render) INI(w io.Writer, status int, v interface{}) error {
+	head := Head{
+		ContentType: ContentText + r.compiledCharset,
+		Status:      status,
+	}
+
+	i := INI{
+		Head: head,
+	}
+
+	return r.Render(w, i, v)
+}
+
+// Image encodes the given image in the given format and writes the image response.
+func (r *Render) Image(w io.Writer, status int, v image.Image, format string) error {
+	head := Head{
+		Status: status,
+	}
+
+	i := Image{
+		Head:   head,
+		Format: format,
+	}
+
+	return r.Render(w, i, v)
+}
+
+// JSON marshals the given interface object and writes the JSON response.
+func (r *Render) JSON(w io.Writer, status int, v interface{}) error {
+	head := Head{
+		ContentType: ContentJSON + r.compiledCharset,
+		Status:      status,
+	}
+
+	j := JSON{
+		Head:          head,
+		Indent:        r.opt.IndentJSON,
+		Prefix:        r.opt.PrefixJSON,
+		SecurePrefix:  r.opt.SecurePrefixJSON,
+		UnEscapeHTML:  r.opt.UnEscapeHTML,
+		StreamingJSON: r.opt.StreamingJSON,
+		SortKeys:      r.opt.SortJSONKeys,
+		TimeFormat:    r.opt.TimeFormatJSON,
+		TagName:       r.opt.TagNameJSON,
+	}
+
+	return r.Render(w, j, v)
+}
+
+// JSONAPI shapes the given resource or collection and writes the JSON:API response.
+func (r *Render) JSONAPI(w io.Writer, status int, v interface{}) error {
+	head := Head{
+		ContentType: ContentJSONAPI,
+		Status:      status,
+	}
+
+	j := JSONAPI{
+		Head:   head,
+		Indent: r.opt.IndentJSON,
+	}
+
+	return r.Render(w, j, v)
+}
+
+// JSONP marshals the given interface object and writes the JSON response.
+func (r *Render) JSONP(w io.Writer, status int, callback string, v interface{}) error {
+	head := Head{
+		ContentType: ContentJSONP + r.compiledCharset,
+		Status:      status,
+	}
+
+	j := JSONP{
+		Head:     head,
+		Indent:   r.opt.IndentJSON,
+		Callback: callback,
+	}
+
+	return r.Render(w, j, v)
+}
+
+// JSONLines writes out each element of the given slice, array or channel as a line of JSON.
+func (r *Render) JSONLines(w io.Writer, status int, v interface{}) error {
+	head := Head{
+		ContentType: ContentNDJSON + r.compiledCharset,
+		Status:      status,
+	}
+
+	j := JSONLines{
+		Head: head,
+	}
+
+	return r.Render(w, j, v)
+}
+
+// MsgPack marshals the given interface object and writes the MessagePack response.
+// Structs must implement the msgp encoding interfaces, usually through `msgp` code generation.
+func (r *Render) MsgPack(w io.Writer, status int, v interface{}) error {
+	head := Head{
+		ContentType: ContentMsgPack,
+		Status:      status,
+	}
+
+	m := MsgPack{
+		Head:      head,
+		Streaming: r.opt.StreamingMsgPack,
+	}
+
+	return r.Render(w, m, v)
+}
+
+// Plist encodes the given interface object and writes the XML property list response.
+func (r *Render) Plist(w io.Writer, status int, v interface{}) error {
+	head := Head{
+		ContentType: ContentPlist,
+		Status:      status,
+	}
+
+	p := Plist{
+		Head: head,
+	}
+
+	return r.Render(w, p, v)
+}
+
+// Problem writes out the given problem details as JSON. The status defaults to the problem's status when zero.
+func (r *Render) Problem(w io.Writer, status int, v ProblemDetails) error {
+	head := Head{
+		ContentType: ContentProblemJSON + r.compiledCharset,
+		Status:      status,
+	}
+
+	p := Problem{
+		Head:   head,
+		Indent: r.opt.IndentJSON,
+	}
+
+	return r.Render(w, p, v)
+}
+
+// Protobuf marshals the given message and writes the Protobuf response.
+func (r *Render) Protobuf(w io.Writer, status int, v proto.Message) error {
+	head := Head{
+		ContentType: ContentProtobuf,
+		Status:      status,
+	}
+
+	p := Protobuf{
+		Head:          head,
+		Deterministic: r.opt.DeterministicProtobuf,
+	}
+
+	return r.Render(w, p, v)
+}
+
+// RSS writes out the given feed as a RSS feed.
+func (r *Render) RSS(w io.Writer, status int, v Feed) error {
+	head := Head{
+		ContentType: ContentRSS + r.compiledCharset,
+		Status:      status,
+	}
+
+	x := RSS{
+		Head:   head,
+		Indent: r.opt.IndentXML,
+	}
+
+	return r.Render(w, x, v)
+}
+
+// Sitemap writes out the given URLs as a sitemap.
+func (r *Render) Sitemap(w io.Writer, status int, v []SitemapURL) error {
+	head := Head{
+		ContentType: ContentSitemap + r.compiledCharset,
+		Status:      status,
+	}
+
+	s := Sitemap{
+		Head:   head,
+		Indent: r.opt.IndentXML,
+	}
+
+	return r.Render(w, s, v)
+}
+
+// SitemapIndex writes out the given sitemaps as a sitemap index.
+func (r *Render) SitemapIndex(w io.Writer, status int, v []SitemapFile) error {
+	head := Head{
+		ContentType: ContentSitemap + r.compiledCharset,
+		Status:      status,
+	}
+
+	s := SitemapIndex{
+		Head:   head,
+		Indent: r.opt.IndentXML,
+	}
+
+	return r.Render(w, s, v)
+}
+
+// SRT writes out the given cues as SubRip subtitles.
+func (r *Render) SRT(w io.Writer, status int, v []Cue) error {
+	head := Head{
+		ContentType: ContentSRT + r.compiledCharset,
+		Status:      status,
+	}
+
+	s := SRT{
+		Head: head,
+	}
+
+	return r.Render(w, s, v)
+}
+
+// Table writes out a slice of structs as an HTML table.
+func (r *Render) Table(w io.Writer, status int, v interface{}) error {
+	head := Head{
+		ContentType: r.opt.HTMLContentType + r.compiledCharset,
+		Status:      status,
+	}
+
+	t := Table{
+		Head: head,
+	}
+
+	return r.Render(w, t, v)
+}
+
+// Text writes out a string as plain text.
+func (r *Render) Text(w io.Writer, status int, v string) error {
+	head := Head{
+		ContentType: ContentText + r.compiledCharset,
+		Status:      status,
+	}
+
+	t := Text{
+		Head: head,
+	}
+
+	return r.Render(w, t, v)
+}
+
+// TOML encodes the given interface object and writes the TOML response.
+func (r *Render) TOML(w io.Writer, status int, v interface{}) error {
+	head := Head{
+		ContentType: ContentTOML + r.compiledCharset,
+		Status:      status,
+	}
+
+	t := TOML{
+		Head:   head,
+		Indent: r.opt.IndentTOML,
+		Prefix: r.opt.PrefixTOML,
+	}
+
+	return r.Render(w, t, v)
+}
+
+// VCard writes out the given contact, or contacts, as vCard 3.0.
+func (r *Render) VCard(w io.Writer, status int, v interface{}) error {
+	head := Head{
+		ContentType: ContentVCard + r.compiledCharset,
+		Status:      status,
+	}
+
+	c := VCard{
+		Head: head,
+	}
+
+	return r.Render(w, c, v)
+}
+
+// VTT writes out the given cues as WebVTT subtitles.
+func (r *Render) VTT(w io.Writer, status int, v []Cue) error {
+	head := Head{
+		ContentType: ContentVTT + r.compiledCharset,
+		Status:      status,
+	}
+
+	s := VTT{
+		Head: head,
+	}
+
+	return r.Render(w, s, v)
+}
+
+// XML marshals the given interface object and writes the XML response.
+func (r *Render) XML(w io.Writer, status int, v interface{}) error {
+	head := Head{
+		ContentType: ContentXML + r.compiledCharset,
+		Status:      status,
+	}
+
+	x := XML{
+		Head:         head,
+		Indent:       r.opt.IndentXML,
+		Prefix:       r.opt.PrefixXML,
+		XMLHeader:    r.opt.HeaderXML,
+		RootElement:  r.opt.RootElementXML,
+		StreamingXML: r.opt.StreamingXML,
+	}
+
+	return r.Render(w, x, v)
+}
+
+// YAML marshals the given interface object and writes the YAML response.
+func (r *Render) YAML(w io.Writer, status int, v interface{}) error {
+	head := Head{
+		ContentType: ContentYAML + r.compiledCharset,
+		Status:      status,
+	}
+
+	y := YAML{
+		Head:   head,
+		Indent: r.opt.IndentYAML,
+		Prefix: r.opt.PrefixYAML,
+	}
+
+	return r.Render(w, y, v)
+}
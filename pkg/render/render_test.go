@@ -0,0 +1,299 @@
+package render
+
+import (
+	"html/template"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender_RenderStatus_concurrent(t *testing.T) {
+	r := New()
+	engine := JSON{Head: Head{Status: http.StatusOK}}
+	statuses := []int{http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError}
+
+	recorders := make([]*httptest.ResponseRecorder, 100)
+	var wg sync.WaitGroup
+	for i := range recorders {
+		recorders[i] = httptest.NewRecorder()
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := r.RenderStatus(recorders[i], engine, statuses[i%len(statuses)], map[string]int{"i": i})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, recorder := range recorders {
+		assert.Equal(t, statuses[i%len(statuses)], recorder.Code)
+	}
+	assert.Equal(t, http.StatusOK, engine.Status)
+}
+
+// headlessEngine is an engine without a Head, writing a 200 itself.
+type headlessEngine struct{}
+
+func (headlessEngine) Render(w io.Writer, v interface{}) error {
+	if hw, ok := w.(http.ResponseWriter); ok {
+		hw.WriteHeader(http.StatusOK)
+	}
+	_, err := io.WriteString(w, v.(string))
+	return err
+}
+
+func TestRender_RenderStatus_engineStatus(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		engine   Engine
+		value    interface{}
+		expected int
+	}{
+		{
+			desc:     "engine writing 200",
+			engine:   Text{Head: Head{Status: http.StatusOK}},
+			value:    "hello",
+			expected: http.StatusCreated,
+		},
+		{
+			desc:     "engine configured with another status",
+			engine:   Text{Head: Head{Status: http.StatusNotFound}},
+			value:    "hello",
+			expected: http.StatusCreated,
+		},
+		{
+			desc:     "pointer engine",
+			engine:   &JSON{Head: Head{Status: http.StatusAccepted}},
+			value:    "hello",
+			expected: http.StatusCreated,
+		},
+		{
+			desc:     "wrapper engine",
+			engine:   Gzip{Engine: Text{Head: Head{Status: http.StatusNotFound}}},
+			value:    "hello",
+			expected: http.StatusCreated,
+		},
+		{
+			desc:     "cached engine",
+			engine:   Cached{Engine: Text{Head: Head{Status: http.StatusOK}}, Key: func(v interface{}) string { return v.(string) }, Cache: NewRenderCache(0, 0, 0)},
+			value:    "hello",
+			expected: http.StatusCreated,
+		},
+		{
+			desc:     "engine without a head",
+			engine:   Tee{Engine: headlessEngine{}, Writer: ioutil.Discard},
+			value:    "hello",
+			expected: http.StatusCreated,
+		},
+		{
+			desc:     "no content",
+			engine:   JSON{Head: Head{Status: http.StatusOK}, Nil: NilAsNoContent},
+			value:    nil,
+			expected: http.StatusNoContent,
+		},
+		{
+			desc:     "not modified",
+			engine:   Data{Head: Head{Status: http.StatusOK}, LastModified: time.Unix(0, 0), IfModifiedSince: time.Unix(0, 0).UTC().Format(http.TimeFormat)},
+			value:    []byte("hello"),
+			expected: http.StatusNotModified,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			err := New().RenderStatus(recorder, test.engine, http.StatusCreated, test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, recorder.Code)
+		})
+	}
+}
+
+func TestEngines_concurrentRender(t *testing.T) {
+	tmpl := newTestTemplates(t)
+
+	layout := HTML{
+		Head:      Head{Status: http.StatusOK, Headers: http.Header{ContentSecurityPolicy: {"script-src 'self'"}}},
+		Templates: tmpl,
+		Name:      "page",
+		Layout:    "base",
+		Funcs:     template.FuncMap{"upper": strings.ToUpper},
+		Nonce:     true,
+	}
+	require.NoError(t, layout.Validate())
+
+	type item struct {
+		A int
+	}
+
+	ok := Head{Status: http.StatusOK}
+	testCases := []struct {
+		desc   string
+		engine Engine
+		value  interface{}
+		// random is set for engines whose output differs between renders.
+		random bool
+	}{
+		{
+			desc:   "validated HTML layout with funcs and nonce",
+			engine: layout,
+			value:  "page",
+			random: true,
+		},
+		{
+			desc:   "HTML layout",
+			engine: HTML{Head: ok, Templates: tmpl, Name: "page", Layout: "base"},
+			value:  "page",
+		},
+		{
+			desc:   "HTML block",
+			engine: HTML{Head: ok, Templates: tmpl, Block: "page"},
+			value:  "page",
+		},
+		{
+			desc:   "JSON",
+			engine: JSON{Head: ok, Indent: true, SortKeys: true, Fields: []string{"a", "c"}},
+			value:  map[string]interface{}{"c": []int{1, 2}, "a": time.Unix(0, 0).UTC(), "b": true},
+		},
+		{
+			desc:   "Text transcoded",
+			engine: Text{Head: Head{Status: http.StatusOK, Vary: []string{"Accept"}}, AcceptCharset: "iso-8859-1", Transcoders: map[string]Transcoder{"iso-8859-1": TranscodeLatin1}},
+			value:  "café",
+		},
+		{
+			desc:   "XML",
+			engine: XML{Head: ok, Indent: true, XMLHeader: true},
+			value:  item{A: 1},
+		},
+		{
+			desc:   "gzip",
+			engine: Gzip{Engine: JSON{Head: ok}, AcceptEncoding: "gzip"},
+			value:  []string{"a", "b"},
+		},
+		{
+			desc:   "ETag",
+			engine: ETag{Engine: JSON{Head: ok}},
+			value:  []string{"a", "b"},
+		},
+		{
+			desc: "cached",
+			engine: Cached{
+				Engine: JSON{Head: ok},
+				Key:    func(interface{}) string { return "key" },
+				Cache:  NewRenderCache(time.Minute, 10, 0),
+			},
+			value: []string{"a", "b"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			expected := httptest.NewRecorder()
+			err := test.engine.Render(expected, test.value)
+			require.NoError(t, err)
+
+			recorders := make([]*httptest.ResponseRecorder, 50)
+			var wg sync.WaitGroup
+			for i := range recorders {
+				recorders[i] = httptest.NewRecorder()
+
+				wg.Add(1)
+				go func(recorder *httptest.ResponseRecorder) {
+					defer wg.Done()
+					assert.NoError(t, test.engine.Render(recorder, test.value))
+				}(recorders[i])
+			}
+			wg.Wait()
+
+			for _, recorder := range recorders {
+				assert.Equal(t, expected.Code, recorder.Code)
+				if !test.random {
+					assert.Equal(t, expected.Body.String(), recorder.Body.String())
+					assert.Equal(t, expected.Header(), recorder.Header())
+				}
+			}
+		})
+	}
+}
+
+func TestRender_RenderRequest(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		req            *http.Request
+		engine         Engine
+		value          interface{}
+		expectedLength string
+		expectedBody   string
+	}{
+		{
+			desc:           "GET",
+			req:            httptest.NewRequest(http.MethodGet, "/", nil),
+			engine:         JSON{Head: Head{Status: http.StatusOK}, Indent: true},
+			value:          map[string]int{"a": 1},
+			expectedLength: "13",
+			expectedBody:   "{\n  \"a\": 1\n}\n",
+		},
+		{
+			desc:           "HEAD",
+			req:            httptest.NewRequest(http.MethodHead, "/", nil),
+			engine:         JSON{Head: Head{Status: http.StatusOK}, Indent: true},
+			value:          map[string]int{"a": 1},
+			expectedLength: "13",
+		},
+		{
+			desc:   "HEAD streaming",
+			req:    httptest.NewRequest(http.MethodHead, "/", nil),
+			engine: JSON{Head: Head{Status: http.StatusOK}, StreamingJSON: true},
+			value:  []int{1, 2},
+		},
+		{
+			desc:         "nil request",
+			engine:       Text{Head: Head{Status: http.StatusOK}},
+			value:        "hello",
+			expectedBody: "hello",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			err := New().RenderRequest(recorder, test.req, test.engine, test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, test.expectedLength, recorder.Header().Get(ContentLength))
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestRender_RenderRequest_error(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodHead, "/", nil)
+
+	err := New().RenderRequest(recorder, req, JSON{Head: Head{Status: http.StatusOK}}, make(chan int))
+	require.Error(t, err)
+
+	// The error response has no body either.
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Empty(t, recorder.Body.String())
+}
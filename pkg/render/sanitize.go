@@ -0,0 +1,212 @@
+package render
+
+import (
+	"bytes"
+	"html"
+	"strings"
+)
+
+// sanitizeAllowedTags maps the elements kept by sanitizeHTML to their allowed attributes.
+var sanitizeAllowedTags = map[string][]string{
+	"a": {"href", "title"}, "abbr": {"title"}, "b": nil, "blockquote": nil, "br": nil,
+	"code": {"class"}, "dd": nil, "del": nil, "dl": nil, "dt": nil, "em": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil, "hr": nil,
+	"i": nil, "img": {"src", "alt", "title"}, "ins": nil, "kbd": nil, "li": nil,
+	"ol": {"start"}, "p": nil, "pre": nil, "s": nil, "strong": nil, "sub": nil,
+	"sup": nil, "table": nil, "tbody": nil, "td": {"align"}, "tfoot": nil,
+	"th": {"align"}, "thead": nil, "tr": nil, "ul": nil,
+}
+
+// sanitizeDroppedTags are the elements removed along with their content.
+var sanitizeDroppedTags = []string{"script", "style", "iframe", "object", "embed", "template", "textarea", "title"}
+
+// sanitizeHTML is an allowlist HTML sanitizer. Elements and attributes which
+// aren't allowed are removed, as are comments and URLs of other schemes than
+// http, https and mailto. Text is kept, a stray '<' being escaped.
+func sanitizeHTML(src []byte) []byte {
+	var dst bytes.Buffer
+	for i := 0; i < len(src); {
+		c := src[i]
+		if c != '<' {
+			if c == '>' {
+				dst.WriteString("&gt;")
+			} else {
+				dst.WriteByte(c)
+			}
+			i++
+			continue
+		}
+
+		if bytes.HasPrefix(src[i:], []byte("<!--")) {
+			end := bytes.Index(src[i+4:], []byte("-->"))
+			if end == -1 {
+				break
+			}
+			i += 4 + end + 3
+			continue
+		}
+
+		end := tagEnd(src[i:])
+		name, closing := tagName(src[i+1:])
+		if end == -1 || name == "" {
+			dst.WriteString("&lt;")
+			i++
+			continue
+		}
+		tag := src[i : i+end+1]
+		i += end + 1
+
+		if !closing && isDroppedTag(name) {
+			// Skip everything up to and including the closing tag.
+			if j := indexFold(src[i:], "</"+name); j != -1 {
+				i += j
+				if k := bytes.IndexByte(src[i:], '>'); k != -1 {
+					i += k + 1
+					continue
+				}
+			}
+			i = len(src)
+			continue
+		}
+
+		attrs, ok := sanitizeAllowedTags[name]
+		if !ok {
+			continue
+		}
+		if closing {
+			dst.WriteString("</" + name + ">")
+			continue
+		}
+		dst.WriteString("<" + name)
+		for _, attr := range tagAttributes(tag[1+len(name):]) {
+			if !containsString(attrs, attr[0]) {
+				continue
+			}
+			if (attr[0] == "href" || attr[0] == "src") && !isSafeURL(attr[1]) {
+				continue
+			}
+			dst.WriteString(" " + attr[0] + `="` + html.EscapeString(attr[1]) + `"`)
+		}
+		dst.WriteByte('>')
+	}
+	return dst.Bytes()
+}
+
+// tagEnd returns the index of the '>' ending the tag starting at b, skipping
+// quoted attribute values, or -1.
+func tagEnd(b []byte) int {
+	var quote byte
+	for i := 1; i < len(b); i++ {
+		switch {
+		case quote != 0:
+			if b[i] == quote {
+				quote = 0
+			}
+		case b[i] == '"' || b[i] == '\'':
+			quote = b[i]
+		case b[i] == '>':
+			return i
+		}
+	}
+	return -1
+}
+
+// tagName returns the lower cased name of the tag following a '<', and
+// whether it's a closing tag.
+func tagName(b []byte) (string, bool) {
+	closing := len(b) > 0 && b[0] == '/'
+	if closing {
+		b = b[1:]
+	}
+	n := 0
+	for n < len(b) && (b[n] >= 'a' && b[n] <= 'z' || b[n] >= 'A' && b[n] <= 'Z' || n > 0 && b[n] >= '0' && b[n] <= '9') {
+		n++
+	}
+	if n == 0 {
+		return "", false
+	}
+	return strings.ToLower(string(b[:n])), closing
+}
+
+// tagAttributes parses the attributes following a tag name into lower cased
+// names and unescaped values.
+func tagAttributes(b []byte) [][2]string {
+	var attrs [][2]string
+	i := 0
+	for i < len(b) {
+		for i < len(b) && (isHTMLSpace(b[i]) || b[i] == '/' || b[i] == '>') {
+			i++
+		}
+		start := i
+		for i < len(b) && !isHTMLSpace(b[i]) && b[i] != '=' && b[i] != '>' && b[i] != '/' {
+			i++
+		}
+		if start == i {
+			break
+		}
+		name := strings.ToLower(string(b[start:i]))
+
+		for i < len(b) && isHTMLSpace(b[i]) {
+			i++
+		}
+		var value string
+		if i < len(b) && b[i] == '=' {
+			i++
+			for i < len(b) && isHTMLSpace(b[i]) {
+				i++
+			}
+			if i < len(b) && (b[i] == '"' || b[i] == '\'') {
+				quote := b[i]
+				i++
+				start = i
+				for i < len(b) && b[i] != quote {
+					i++
+				}
+				value = string(b[start:i])
+				i++
+			} else {
+				start = i
+				for i < len(b) && !isHTMLSpace(b[i]) && b[i] != '>' {
+					i++
+				}
+				value = string(b[start:i])
+			}
+		}
+		attrs = append(attrs, [2]string{name, html.UnescapeString(value)})
+	}
+	return attrs
+}
+
+// isSafeURL reports whether u is relative or of the http, https or mailto scheme.
+func isSafeURL(u string) bool {
+	// Browsers ignore control characters and spaces within schemes.
+	u = strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, u)
+
+	colon := strings.IndexByte(u, ':')
+	if colon == -1 || strings.IndexAny(u[:colon], "/?#") != -1 {
+		return true
+	}
+	switch strings.ToLower(u[:colon]) {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+func isDroppedTag(name string) bool {
+	return containsString(sanitizeDroppedTags, name)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
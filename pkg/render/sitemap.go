@@ -0,0 +1,206 @@
+package render
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Limits of a single sitemap or sitemap index file set by the sitemaps.org
+// protocol. Larger sites must split their URLs across several sitemaps listed
+// by a sitemap index.
+const (
+	SitemapMaxURLs = 50000
+	SitemapMaxSize = 50 * 1024 * 1024
+)
+
+const sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// SitemapURL is a single URL of a sitemap.
+type SitemapURL struct {
+	Loc     string
+	LastMod time.Time
+	// ChangeFreq is one of always, hourly, daily, weekly, monthly, yearly and never.
+	ChangeFreq string
+	// Priority ranges from 0.0 to 1.0 and is omitted when zero, the protocol
+	// default being 0.5.
+	Priority float64
+}
+
+// Sitemap built-in renderer for sitemaps.org sitemaps. It fails when the URLs
+// exceed SitemapMaxURLs or the output exceeds SitemapMaxSize.
+type Sitemap struct {
+	Head
+	Indent bool
+}
+
+// SitemapFile is a sitemap listed by a sitemap index.
+type SitemapFile struct {
+	Loc     string
+	LastMod time.Time
+}
+
+// SitemapIndex built-in renderer for sitemaps.org sitemap indexes. Like Sitemap,
+// it fails when the sitemaps exceed SitemapMaxURLs or the output exceeds SitemapMaxSize.
+type SitemapIndex struct {
+	Head
+	Indent bool
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name            `xml:"urlset"`
+	Xmlns   string              `xml:"xmlns,attr"`
+	URLs    []sitemapURLElement `xml:"url"`
+}
+
+type sitemapURLElement struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod,omitempty"`
+	ChangeFreq string `xml:"changefreq,omitempty"`
+	Priority   string `xml:"priority,omitempty"`
+}
+
+// Render a sitemap response.
+func (s Sitemap) Render(w io.Writer, v interface{}) error {
+	var urls []SitemapURL
+	switch u := v.(type) {
+	case []SitemapURL:
+		urls = u
+	case *[]SitemapURL:
+		if u == nil {
+			return errors.New("render: Sitemap cannot render a nil *[]SitemapURL")
+		}
+		urls = *u
+	default:
+		return fmt.Errorf("render: Sitemap expects []SitemapURL, got %T", v)
+	}
+	if len(urls) > SitemapMaxURLs {
+		return fmt.Errorf("render: Sitemap has %d URLs, over the limit of %d, use a sitemap index", len(urls), SitemapMaxURLs)
+	}
+
+	doc := sitemapURLSet{
+		Xmlns: sitemapNamespace,
+		URLs:  make([]sitemapURLElement, 0, len(urls)),
+	}
+	for _, u := range urls {
+		if err := checkSitemapLoc(u.Loc); err != nil {
+			return err
+		}
+		switch u.ChangeFreq {
+		case "", "always", "hourly", "daily", "weekly", "monthly", "yearly", "never":
+		default:
+			return fmt.Errorf("render: Sitemap URL %q has an invalid changefreq %q", u.Loc, u.ChangeFreq)
+		}
+		if u.Priority < 0 || u.Priority > 1 {
+			return fmt.Errorf("render: Sitemap URL %q has a priority out of the 0.0 to 1.0 range", u.Loc)
+		}
+
+		elem := sitemapURLElement{
+			Loc:        u.Loc,
+			LastMod:    sitemapDate(u.LastMod),
+			ChangeFreq: u.ChangeFreq,
+		}
+		if u.Priority != 0 {
+			elem.Priority = strconv.FormatFloat(u.Priority, 'f', -1, 64)
+		}
+		doc.URLs = append(doc.URLs, elem)
+	}
+
+	return renderSitemapDocument(w, s.Head, s.Indent, doc)
+}
+
+type sitemapIndexDoc struct {
+	XMLName  xml.Name             `xml:"sitemapindex"`
+	Xmlns    string               `xml:"xmlns,attr"`
+	Sitemaps []sitemapFileElement `xml:"sitemap"`
+}
+
+type sitemapFileElement struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Render a sitemap index response.
+func (s SitemapIndex) Render(w io.Writer, v interface{}) error {
+	var files []SitemapFile
+	switch f := v.(type) {
+	case []SitemapFile:
+		files = f
+	case *[]SitemapFile:
+		if f == nil {
+			return errors.New("render: SitemapIndex cannot render a nil *[]SitemapFile")
+		}
+		files = *f
+	default:
+		return fmt.Errorf("render: SitemapIndex expects []SitemapFile, got %T", v)
+	}
+	if len(files) > SitemapMaxURLs {
+		return fmt.Errorf("render: SitemapIndex has %d sitemaps, over the limit of %d", len(files), SitemapMaxURLs)
+	}
+
+	doc := sitemapIndexDoc{
+		Xmlns:    sitemapNamespace,
+		Sitemaps: make([]sitemapFileElement, 0, len(files)),
+	}
+	for _, f := range files {
+		if err := checkSitemapLoc(f.Loc); err != nil {
+			return err
+		}
+		doc.Sitemaps = append(doc.Sitemaps, sitemapFileElement{
+			Loc:     f.Loc,
+			LastMod: sitemapDate(f.LastMod),
+		})
+	}
+
+	return renderSitemapDocument(w, s.Head, s.Indent, doc)
+}
+
+// renderSitemapDocument renders a sitemap or sitemap index document along with
+// the XML declaration, failing when it exceeds SitemapMaxSize.
+func renderSitemapDocument(w io.Writer, head Head, indent bool, doc interface{}) error {
+	// Retrieve a buffer from the pool to encode into.
+	buf := bufPool.Get()
+	defer bufPool.Put(buf)
+
+	x := XML{
+		Indent:    indent,
+		XMLHeader: true,
+	}
+	if err := x.Render(buf, doc); err != nil {
+		return err
+	}
+	if buf.Len() > SitemapMaxSize {
+		return fmt.Errorf("render: sitemap is %d bytes, over the limit of %d", buf.Len(), SitemapMaxSize)
+	}
+
+	if hw, ok := w.(http.ResponseWriter); ok {
+		hw.Header().Set(ContentLength, strconv.Itoa(buf.Len()))
+		head.setDefaultContentType(ContentSitemap)
+		head.Write(hw)
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// checkSitemapLoc checks a location is set and within the 2,048 characters limit.
+func checkSitemapLoc(loc string) error {
+	if loc == "" {
+		return errors.New("render: sitemap entry has no location")
+	}
+	if len(loc) > 2048 {
+		return fmt.Errorf("render: sitemap location %.64q... is over 2048 characters", loc)
+	}
+	return nil
+}
+
+// sitemapDate formats t as a W3C datetime, or returns an empty string for the zero time.
+func sitemapDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
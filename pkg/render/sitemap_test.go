@@ -0,0 +1,165 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSitemap_Render(t *testing.T) {
+	urls := []SitemapURL{
+		{Loc: "https://example.com/", LastMod: time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC), ChangeFreq: "daily", Priority: 0.8},
+		{Loc: "https://example.com/about"},
+	}
+	expected := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` +
+		`<url><loc>https://example.com/</loc><lastmod>2019-01-02T00:00:00Z</lastmod><changefreq>daily</changefreq><priority>0.8</priority></url>` +
+		`<url><loc>https://example.com/about</loc></url>` +
+		`</urlset>`
+
+	testCases := []struct {
+		desc  string
+		value interface{}
+	}{
+		{
+			desc:  "slice",
+			value: urls,
+		},
+		{
+			desc:  "pointer to a slice",
+			value: &urls,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			err := Sitemap{Head: Head{Status: http.StatusOK}}.Render(recorder, test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, recorder.Body.String())
+		})
+	}
+}
+
+func TestSitemap_Render_invalidValue(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		value interface{}
+	}{
+		{
+			desc:  "nil pointer",
+			value: (*[]SitemapURL)(nil),
+		},
+		{
+			desc:  "nil",
+			value: nil,
+		},
+		{
+			desc:  "missing location",
+			value: []SitemapURL{{}},
+		},
+		{
+			desc:  "invalid changefreq",
+			value: []SitemapURL{{Loc: "https://example.com/", ChangeFreq: "sometimes"}},
+		},
+		{
+			desc:  "priority out of range",
+			value: []SitemapURL{{Loc: "https://example.com/", Priority: 2}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			err := Sitemap{Head: Head{Status: http.StatusOK}}.Render(recorder, test.value)
+			require.Error(t, err)
+
+			assert.Empty(t, recorder.Header())
+			assert.Empty(t, recorder.Body.String())
+		})
+	}
+}
+
+func TestSitemapIndex_Render(t *testing.T) {
+	files := []SitemapFile{
+		{Loc: "https://example.com/sitemap1.xml", LastMod: time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{Loc: "https://example.com/sitemap2.xml"},
+	}
+	expected := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` +
+		`<sitemap><loc>https://example.com/sitemap1.xml</loc><lastmod>2019-01-02T00:00:00Z</lastmod></sitemap>` +
+		`<sitemap><loc>https://example.com/sitemap2.xml</loc></sitemap>` +
+		`</sitemapindex>`
+
+	testCases := []struct {
+		desc  string
+		value interface{}
+	}{
+		{
+			desc:  "slice",
+			value: files,
+		},
+		{
+			desc:  "pointer to a slice",
+			value: &files,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			err := SitemapIndex{Head: Head{Status: http.StatusOK}}.Render(recorder, test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, recorder.Body.String())
+		})
+	}
+}
+
+func TestSitemapIndex_Render_invalidValue(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		value interface{}
+	}{
+		{
+			desc:  "nil pointer",
+			value: (*[]SitemapFile)(nil),
+		},
+		{
+			desc:  "nil",
+			value: nil,
+		},
+		{
+			desc:  "missing location",
+			value: []SitemapFile{{}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			err := SitemapIndex{Head: Head{Status: http.StatusOK}}.Render(recorder, test.value)
+			require.Error(t, err)
+
+			assert.Empty(t, recorder.Header())
+			assert.Empty(t, recorder.Body.String())
+		})
+	}
+}
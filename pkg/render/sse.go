@@ -0,0 +1,121 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Event is a single Server-Sent Event. Data is written as is when it's a string
+// or a []byte and JSON encoded otherwise.
+type Event struct {
+	ID    string
+	Event string
+	Retry int
+	Data  interface{}
+}
+
+// sseLineBreaks normalizes the line breaks of event data, a lone "\r" ending
+// a line as well.
+var sseLineBreaks = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+// SSE built-in renderer, writing Server-Sent Events received from a channel.
+type SSE struct {
+	Head
+}
+
+// Render a Server-Sent Events response.
+func (s SSE) Render(w io.Writer, v interface{}) error {
+	return s.RenderCtx(context.Background(), w, v)
+}
+
+// RenderCtx renders a Server-Sent Events response until the channel is closed
+// or ctx is done. Channel elements which aren't an Event are sent as the event data.
+func (s SSE) RenderCtx(ctx context.Context, w io.Writer, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Chan && rv.Kind() != reflect.Slice {
+		return fmt.Errorf("render: SSE expects a channel or a slice, got %T", v)
+	}
+	if rv.Kind() == reflect.Chan && rv.Type().ChanDir()&reflect.RecvDir == 0 {
+		return fmt.Errorf("render: SSE cannot receive from %T", v)
+	}
+
+	if hw, ok := w.(http.ResponseWriter); ok {
+		if hw.Header().Get(CacheControl) == "" {
+			hw.Header().Set(CacheControl, "no-cache")
+		}
+		s.Head.setDefaultContentType(ContentEventStream)
+		s.Head.Write(hw)
+	}
+
+	return eachElement(ctx, rv, func(e interface{}) error {
+		var event Event
+		switch ev := e.(type) {
+		case Event:
+			event = ev
+		case *Event:
+			if ev == nil {
+				return errors.New("render: SSE cannot render a nil *Event")
+			}
+			event = *ev
+		default:
+			event = Event{Data: e}
+		}
+
+		b, err := event.marshal()
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+		flush(w)
+		return nil
+	})
+}
+
+// marshal frames the event, terminating it with a blank line.
+func (e Event) marshal() ([]byte, error) {
+	var data string
+	switch d := e.Data.(type) {
+	case string:
+		data = d
+	case []byte:
+		data = string(d)
+	default:
+		b, err := json.Marshal(d)
+		if err != nil {
+			return nil, err
+		}
+		data = string(b)
+	}
+
+	var buf bytes.Buffer
+	if e.ID != "" {
+		buf.WriteString("id: " + stripNewlines(e.ID) + "\n")
+	}
+	if e.Event != "" {
+		buf.WriteString("event: " + stripNewlines(e.Event) + "\n")
+	}
+	if e.Retry > 0 {
+		buf.WriteString("retry: " + strconv.Itoa(e.Retry) + "\n")
+	}
+	// Multi-line data is sent as consecutive data fields.
+	for _, line := range strings.Split(sseLineBreaks.Replace(data), "\n") {
+		buf.WriteString("data: " + line + "\n")
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// stripNewlines removes line breaks which would otherwise end an event field.
+func stripNewlines(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
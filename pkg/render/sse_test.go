@@ -0,0 +1,106 @@
+package render
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSE_Render(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		value    interface{}
+		expected string
+	}{
+		{
+			desc:     "event",
+			value:    []Event{{ID: "1", Event: "message", Retry: 10, Data: "hello"}},
+			expected: "id: 1\nevent: message\nretry: 10\ndata: hello\n\n",
+		},
+		{
+			desc:     "pointer to an event",
+			value:    []*Event{{Data: []byte("hello")}},
+			expected: "data: hello\n\n",
+		},
+		{
+			desc:     "JSON data",
+			value:    []interface{}{map[string]int{"a": 1}},
+			expected: "data: {\"a\":1}\n\n",
+		},
+		{
+			desc:     "multi-line data",
+			value:    []string{"a\nb\r\nc\rd"},
+			expected: "data: a\ndata: b\ndata: c\ndata: d\n\n",
+		},
+		{
+			desc:     "line breaks in fields",
+			value:    []Event{{ID: "1\r\nid: 2", Event: "a\rb", Data: "c"}},
+			expected: "id: 1id: 2\nevent: ab\ndata: c\n\n",
+		},
+		{
+			desc: "channel",
+			value: func() chan string {
+				ch := make(chan string, 2)
+				ch <- "a"
+				ch <- "b"
+				close(ch)
+				return ch
+			}(),
+			expected: "data: a\n\ndata: b\n\n",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			err := SSE{}.Render(buf, test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, buf.String())
+		})
+	}
+}
+
+func TestSSE_Render_invalidValue(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		value    interface{}
+		expected string
+	}{
+		{
+			desc:     "nil event",
+			value:    []*Event{{Data: "a"}, nil, {Data: "b"}},
+			expected: "data: a\n\n",
+		},
+		{
+			desc:  "nil",
+			value: nil,
+		},
+		{
+			desc:  "send-only channel",
+			value: make(chan<- Event),
+		},
+		{
+			desc:  "unmarshalable data",
+			value: []Event{{Data: make(chan int)}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			err := SSE{}.Render(buf, test.value)
+			require.Error(t, err)
+
+			assert.Equal(t, test.expected, buf.String())
+		})
+	}
+}
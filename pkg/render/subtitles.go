@@ -0,0 +1,136 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cue is a single subtitle rendered by the VTT and SRT engines.
+type Cue struct {
+	// ID is the optional identifier of a WebVTT cue, SRT cues being numbered instead.
+	ID    string
+	Start time.Duration
+	End   time.Duration
+	// Text may hold the formatting tags of the format, such as <i>. Blank lines
+	// would end the cue, so they're removed.
+	Text string
+}
+
+var vttIDReplacer = strings.NewReplacer("\r", " ", "\n", " ", "-->", "->")
+
+// VTT built-in renderer for WebVTT subtitles.
+type VTT struct {
+	Head
+}
+
+// SRT built-in renderer for SubRip subtitles.
+type SRT struct {
+	Head
+}
+
+// Render a WebVTT response.
+func (s VTT) Render(w io.Writer, v interface{}) error {
+	cues, err := sortedCues("VTT", v)
+	if err != nil {
+		return err
+	}
+
+	// Retrieve a buffer from the pool to write to.
+	out := bufPool.Get()
+	defer bufPool.Put(out)
+
+	out.WriteString("WEBVTT\n")
+	for _, c := range cues {
+		out.WriteByte('\n')
+		if c.ID != "" {
+			// Identifiers can't hold line breaks nor "-->".
+			out.WriteString(vttIDReplacer.Replace(c.ID) + "\n")
+		}
+		writeCue(out, c, '.', strings.Replace(c.Text, "-->", "--&gt;", -1))
+	}
+
+	return writeSubtitles(w, s.Head, ContentVTT, out)
+}
+
+// Render a SubRip response.
+func (s SRT) Render(w io.Writer, v interface{}) error {
+	cues, err := sortedCues("SRT", v)
+	if err != nil {
+		return err
+	}
+
+	// Retrieve a buffer from the pool to write to.
+	out := bufPool.Get()
+	defer bufPool.Put(out)
+
+	for i, c := range cues {
+		if i > 0 {
+			out.WriteByte('\n')
+		}
+		out.WriteString(strconv.Itoa(i+1) + "\n")
+		writeCue(out, c, ',', c.Text)
+	}
+
+	return writeSubtitles(w, s.Head, ContentSRT, out)
+}
+
+// sortedCues checks the cues of v and returns a copy ordered by start, then end time.
+func sortedCues(engine string, v interface{}) ([]Cue, error) {
+	var cues []Cue
+	switch c := v.(type) {
+	case []Cue:
+		cues = append([]Cue(nil), c...)
+	case *[]Cue:
+		if c == nil {
+			return nil, fmt.Errorf("render: %s cannot render a nil *[]Cue", engine)
+		}
+		cues = append([]Cue(nil), *c...)
+	default:
+		return nil, fmt.Errorf("render: %s expects []Cue, got %T", engine, v)
+	}
+
+	for _, c := range cues {
+		if c.Start < 0 || c.End < c.Start {
+			return nil, fmt.Errorf("render: %s cue %q has invalid times %s --> %s", engine, c.Text, c.Start, c.End)
+		}
+	}
+	sort.SliceStable(cues, func(i, j int) bool {
+		if cues[i].Start != cues[j].Start {
+			return cues[i].Start < cues[j].Start
+		}
+		return cues[i].End < cues[j].End
+	})
+	return cues, nil
+}
+
+// writeCue writes the timings and text lines of a cue, followed by a new line.
+func writeCue(out *bytes.Buffer, c Cue, sep byte, text string) {
+	out.WriteString(cueTimestamp(c.Start, sep) + " --> " + cueTimestamp(c.End, sep) + "\n")
+	for _, line := range strings.Split(strings.Replace(text, "\r\n", "\n", -1), "\n") {
+		if strings.TrimSpace(line) != "" {
+			out.WriteString(line + "\n")
+		}
+	}
+}
+
+// cueTimestamp formats d as HH:MM:SS followed by sep and milliseconds.
+func cueTimestamp(d time.Duration, sep byte) string {
+	ms := d / time.Millisecond
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
+
+func writeSubtitles(w io.Writer, head Head, contentType string, out *bytes.Buffer) error {
+	if hw, ok := w.(http.ResponseWriter); ok {
+		hw.Header().Set(ContentLength, strconv.Itoa(out.Len()))
+		head.setDefaultContentType(contentType)
+		head.Write(hw)
+	}
+	_, err := out.WriteTo(w)
+	return err
+}
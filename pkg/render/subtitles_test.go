@@ -0,0 +1,104 @@
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubtitles_Render(t *testing.T) {
+	cues := []Cue{
+		{ID: "second", Start: 2 * time.Second, End: 3 * time.Second, Text: "world"},
+		{ID: "first", Start: time.Second, End: 1500 * time.Millisecond, Text: "hello\r\n\r\nthere"},
+	}
+
+	testCases := []struct {
+		desc     string
+		engine   Engine
+		value    interface{}
+		expected string
+	}{
+		{
+			desc:     "VTT",
+			engine:   VTT{Head: Head{Status: http.StatusOK}},
+			value:    cues,
+			expected: "WEBVTT\n\nfirst\n00:00:01.000 --> 00:00:01.500\nhello\nthere\n\nsecond\n00:00:02.000 --> 00:00:03.000\nworld\n",
+		},
+		{
+			desc:     "VTT pointer to a slice",
+			engine:   VTT{Head: Head{Status: http.StatusOK}},
+			value:    &cues,
+			expected: "WEBVTT\n\nfirst\n00:00:01.000 --> 00:00:01.500\nhello\nthere\n\nsecond\n00:00:02.000 --> 00:00:03.000\nworld\n",
+		},
+		{
+			desc:     "SRT",
+			engine:   SRT{Head: Head{Status: http.StatusOK}},
+			value:    cues,
+			expected: "1\n00:00:01,000 --> 00:00:01,500\nhello\nthere\n\n2\n00:00:02,000 --> 00:00:03,000\nworld\n",
+		},
+		{
+			desc:     "SRT pointer to a slice",
+			engine:   SRT{Head: Head{Status: http.StatusOK}},
+			value:    &cues,
+			expected: "1\n00:00:01,000 --> 00:00:01,500\nhello\nthere\n\n2\n00:00:02,000 --> 00:00:03,000\nworld\n",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := httptest.NewRecorder()
+			err := test.engine.Render(recorder, test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, recorder.Body.String())
+		})
+	}
+}
+
+func TestSubtitles_Render_invalidValue(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		value interface{}
+	}{
+		{
+			desc:  "nil pointer",
+			value: (*[]Cue)(nil),
+		},
+		{
+			desc:  "nil",
+			value: nil,
+		},
+		{
+			desc:  "negative start",
+			value: []Cue{{Start: -time.Second, End: time.Second}},
+		},
+		{
+			desc:  "end before start",
+			value: []Cue{{Start: 2 * time.Second, End: time.Second}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		for _, engine := range []Engine{VTT{Head: Head{Status: http.StatusOK}}, SRT{Head: Head{Status: http.StatusOK}}} {
+			engine := engine
+			t.Run(test.desc+" "+engineName(engine), func(t *testing.T) {
+				t.Parallel()
+
+				recorder := httptest.NewRecorder()
+				err := engine.Render(recorder, test.value)
+				require.Error(t, err)
+
+				assert.Empty(t, recorder.Header())
+				assert.Empty(t, recorder.Body.String())
+			})
+		}
+	}
+}
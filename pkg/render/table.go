@@ -0,0 +1,53 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// Table built-in renderer, writing a slice of structs as an HTML table with
+// a header row of field names, read from the `table` struct tag when present.
+type Table struct {
+	Head
+}
+
+// Render a HTML table response.
+func (t Table) Render(w io.Writer, v interface{}) error {
+	names, records, ok := structRecords(v, "table")
+	if !ok {
+		return fmt.Errorf("render: Table expects a slice of structs, got %T", v)
+	}
+
+	// Retrieve a buffer from the pool to write to.
+	out := bufPool.Get()
+	defer bufPool.Put(out)
+
+	out.WriteString("<table>\n<thead>\n")
+	writeTableRow(out, "th", names)
+	out.WriteString("</thead>\n<tbody>\n")
+	for _, record := range records {
+		writeTableRow(out, "td", record)
+	}
+	out.WriteString("</tbody>\n</table>\n")
+
+	if hw, ok := w.(http.ResponseWriter); ok {
+		hw.Header().Set(ContentLength, strconv.Itoa(out.Len()))
+		t.Head.setDefaultContentType(ContentHTML)
+		t.Head.Write(hw)
+	}
+	_, err := out.WriteTo(w)
+	return err
+}
+
+// writeTableRow writes a row of escaped cells.
+func writeTableRow(buf *bytes.Buffer, cell string, values []string) {
+	buf.WriteString("<tr>")
+	for _, value := range values {
+		buf.WriteString("<" + cell + ">" + html.EscapeString(value) + "</" + cell + ">")
+	}
+	buf.WriteString("</tr>\n")
+}
@@ -0,0 +1,47 @@
+package render
+
+import (
+	"io"
+	"net/http"
+)
+
+// Tee is a wrapper engine copying the body rendered by another engine to a
+// second writer, e.g. for audit logs or response caching. The body is rendered
+// once into a buffer, then written to both the response and Writer even if one
+// of them fails, the first error being returned. Nothing is written when the
+// inner engine fails.
+type Tee struct {
+	Engine Engine
+	Writer io.Writer
+}
+
+// Render a response, copying its body to the tee writer.
+func (t Tee) Render(w io.Writer, v interface{}) error {
+	var body []byte
+	if hw, ok := w.(http.ResponseWriter); ok {
+		rec := newBufferedResponseWriter()
+		if err := t.Engine.Render(rec, v); err != nil {
+			return err
+		}
+
+		rec.copyHeader(hw)
+		if rec.status != 0 {
+			hw.WriteHeader(rec.status)
+		}
+		body = rec.body.Bytes()
+	} else {
+		buf := bufPool.Get()
+		defer bufPool.Put(buf)
+
+		if err := t.Engine.Render(buf, v); err != nil {
+			return err
+		}
+		body = buf.Bytes()
+	}
+
+	_, err := w.Write(body)
+	if _, teeErr := t.Writer.Write(body); err == nil {
+		err = teeErr
+	}
+	return err
+}
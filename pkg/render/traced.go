@@ -0,0 +1,58 @@
+package render
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// Tracer starts the spans of the Traced engine. It's meant to be a thin adapter
+// around the tracer in use, e.g. OpenTelemetry's or OpenTracing's, so that the
+// package doesn't depend on any of them.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// Traced is a wrapper engine running another engine within a span named
+// "render.<engine>", e.g. "render.JSON". The span records the content type and
+// the number of body bytes written as the "render.content_type" and
+// "render.size" attributes, along with the error if any.
+type Traced struct {
+	Engine Engine
+	Tracer Tracer
+}
+
+// Render a response within a span.
+func (t Traced) Render(w io.Writer, v interface{}) error {
+	return t.RenderCtx(context.Background(), w, v)
+}
+
+// RenderCtx renders a response within a span, child of the one in ctx if any.
+func (t Traced) RenderCtx(ctx context.Context, w io.Writer, v interface{}) error {
+	ctx, span := t.Tracer.Start(ctx, "render."+engineName(t.Engine))
+	defer span.End()
+
+	cw := newCountingWriter(w)
+	var err error
+	if ce, ok := t.Engine.(ContextEngine); ok {
+		err = ce.RenderCtx(ctx, cw, v)
+	} else {
+		err = t.Engine.Render(cw, v)
+	}
+
+	if hw, ok := w.(http.ResponseWriter); ok {
+		span.SetAttribute("render.content_type", hw.Header().Get(ContentType))
+	}
+	span.SetAttribute("render.size", cw.count())
+	if err != nil {
+		span.RecordError(err)
+	}
+	return err
+}
@@ -0,0 +1,34 @@
+//go:build go1.18
+// +build go1.18
+
+package render
+
+import (
+	"context"
+	"io"
+)
+
+// TypedEngine wraps an Engine, fixing at compile time the type of the values it
+// renders, e.g. TypedEngine[[]byte]{Engine: Data{...}}.
+type TypedEngine[T any] struct {
+	Engine Engine
+}
+
+// NewTypedEngine returns a TypedEngine rendering values of type T with e.
+func NewTypedEngine[T any](e Engine) TypedEngine[T] {
+	return TypedEngine[T]{Engine: e}
+}
+
+// Render renders v with the underlying engine.
+func (t TypedEngine[T]) Render(w io.Writer, v T) error {
+	return t.Engine.Render(w, v)
+}
+
+// RenderCtx renders v with the underlying engine, passing ctx along when it
+// implements ContextEngine.
+func (t TypedEngine[T]) RenderCtx(ctx context.Context, w io.Writer, v T) error {
+	if ce, ok := t.Engine.(ContextEngine); ok {
+		return ce.RenderCtx(ctx, w, v)
+	}
+	return t.Engine.Render(w, v)
+}
@@ -0,0 +1,164 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Contact is a contact rendered by the VCard engine.
+type Contact struct {
+	// FormattedName is the name to display, written as FN. It's required.
+	FormattedName string
+	// The components of the structured name, written as N.
+	FamilyName      string
+	GivenName       string
+	AdditionalNames string
+	Prefixes        string
+	Suffixes        string
+	Organization    string
+	Title           string
+	Emails          []string
+	Phones          []ContactPhone
+	Addresses       []ContactAddress
+	URL             string
+	Note            string
+	UID             string
+}
+
+// ContactPhone is a phone number of a Contact. Type is a TYPE parameter value
+// such as "work", "home" or "cell", and may be empty.
+type ContactPhone struct {
+	Type   string
+	Number string
+}
+
+// ContactAddress is a postal address of a Contact, written as ADR. Type is a
+// TYPE parameter value such as "work" or "home", and may be empty.
+type ContactAddress struct {
+	Type       string
+	POBox      string
+	Extended   string
+	Street     string
+	Locality   string
+	Region     string
+	PostalCode string
+	Country    string
+}
+
+// VCard built-in renderer for vCard contacts, in the version 3.0 (RFC 2426) or
+// 4.0 (RFC 6350) format. Several contacts are rendered one after the other.
+type VCard struct {
+	Head
+	// Version is either "3.0" or "4.0". Default is "3.0".
+	Version string
+}
+
+// Render a vCard response.
+func (c VCard) Render(w io.Writer, v interface{}) error {
+	var contacts []Contact
+	switch ct := v.(type) {
+	case Contact:
+		contacts = []Contact{ct}
+	case *Contact:
+		if ct == nil {
+			return errors.New("render: VCard cannot render a nil *Contact")
+		}
+		contacts = []Contact{*ct}
+	case []Contact:
+		contacts = ct
+	default:
+		return fmt.Errorf("render: VCard expects Contact or []Contact, got %T", v)
+	}
+
+	version := c.Version
+	switch version {
+	case "":
+		version = "3.0"
+	case "3.0", "4.0":
+	default:
+		return fmt.Errorf("render: VCard version %q isn't supported, expected 3.0 or 4.0", c.Version)
+	}
+
+	// Retrieve a buffer from the pool to write to.
+	out := bufPool.Get()
+	defer bufPool.Put(out)
+
+	for _, ct := range contacts {
+		if ct.FormattedName == "" {
+			return errors.New("render: VCard contact has no formatted name")
+		}
+
+		writeContentLine(out, "BEGIN:VCARD")
+		writeContentLine(out, "VERSION:"+version)
+		writeContentLine(out, "FN:"+escapeText(ct.FormattedName))
+
+		// N is required by version 3.0 only.
+		name := structuredText(ct.FamilyName, ct.GivenName, ct.AdditionalNames, ct.Prefixes, ct.Suffixes)
+		if version == "3.0" || name != ";;;;" {
+			writeContentLine(out, "N:"+name)
+		}
+		if ct.Organization != "" {
+			writeContentLine(out, "ORG:"+escapeText(ct.Organization))
+		}
+		if ct.Title != "" {
+			writeContentLine(out, "TITLE:"+escapeText(ct.Title))
+		}
+		for _, email := range ct.Emails {
+			writeContentLine(out, "EMAIL:"+escapeText(email))
+		}
+		for _, phone := range ct.Phones {
+			writeContentLine(out, "TEL"+vcardType(phone.Type)+":"+escapeText(phone.Number))
+		}
+		for _, a := range ct.Addresses {
+			writeContentLine(out, "ADR"+vcardType(a.Type)+":"+structuredText(a.POBox, a.Extended, a.Street, a.Locality, a.Region, a.PostalCode, a.Country))
+		}
+		if ct.URL != "" {
+			if err := checkURIValue(ct.URL); err != nil {
+				return err
+			}
+			writeContentLine(out, "URL:"+ct.URL)
+		}
+		if ct.Note != "" {
+			writeContentLine(out, "NOTE:"+escapeText(ct.Note))
+		}
+		if ct.UID != "" {
+			writeContentLine(out, "UID:"+escapeText(ct.UID))
+		}
+		writeContentLine(out, "END:VCARD")
+	}
+
+	if hw, ok := w.(http.ResponseWriter); ok {
+		hw.Header().Set(ContentLength, strconv.Itoa(out.Len()))
+		c.Head.setDefaultContentType(ContentVCard)
+		c.Head.Write(hw)
+	}
+	_, err := out.WriteTo(w)
+	return err
+}
+
+// structuredText joins the components of a structured value such as N or ADR,
+// each one being escaped so that its own semicolons don't split it.
+func structuredText(components ...string) string {
+	for i, component := range components {
+		components[i] = escapeText(component)
+	}
+	return strings.Join(components, ";")
+}
+
+// vcardType returns the TYPE parameter of a property, if any.
+func vcardType(t string) string {
+	if t == "" {
+		return ""
+	}
+	// Parameter values can't hold line breaks, even quoted.
+	t = stripNewlines(t)
+	// Parameter values can't hold these characters unquoted.
+	if strings.ContainsAny(t, `;:,"`) {
+		return `;TYPE="` + strings.Replace(t, `"`, "'", -1) + `"`
+	}
+	return ";TYPE=" + t
+}
@@ -0,0 +1,100 @@
+package render
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVCard_Render(t *testing.T) {
+	contact := Contact{
+		FormattedName: "Jane Doe",
+		FamilyName:    "Doe",
+		GivenName:     "Jane",
+		Phones:        []ContactPhone{{Type: "work\r\nX-INJECTED:1", Number: "+1 555"}},
+		URL:           "https://example.com/?a=1;b=2",
+	}
+	expected := "BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Jane Doe\r\nN:Doe;Jane;;;\r\n" +
+		`TEL;TYPE="workX-INJECTED:1":+1 555` + "\r\n" +
+		"URL:https://example.com/?a=1;b=2\r\nEND:VCARD\r\n"
+
+	testCases := []struct {
+		desc  string
+		value interface{}
+	}{
+		{
+			desc:  "contact",
+			value: contact,
+		},
+		{
+			desc:  "pointer to a contact",
+			value: &contact,
+		},
+		{
+			desc:  "contacts",
+			value: []Contact{contact},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			err := VCard{}.Render(buf, test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, buf.String())
+		})
+	}
+}
+
+func TestVCard_Render_invalidValue(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		engine VCard
+		value  interface{}
+	}{
+		{
+			desc:  "nil contact",
+			value: (*Contact)(nil),
+		},
+		{
+			desc:  "nil",
+			value: nil,
+		},
+		{
+			desc:  "contact without a formatted name",
+			value: Contact{GivenName: "Jane"},
+		},
+		{
+			desc:   "unsupported version",
+			engine: VCard{Version: "2.1"},
+			value:  Contact{FormattedName: "Jane Doe"},
+		},
+		{
+			desc:  "URL with a line break",
+			value: Contact{FormattedName: "Jane Doe", URL: "https://example.com/\r\nEMAIL:a@example.com"},
+		},
+		{
+			desc:  "URL with a new line",
+			value: Contact{FormattedName: "Jane Doe", URL: "https://example.com/\nX"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			err := test.engine.Render(buf, test.value)
+			require.Error(t, err)
+
+			assert.Empty(t, buf.String())
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package render
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// bufferedResponseWriter is an http.ResponseWriter that captures the headers,
+// status and body written by an engine so wrapping engines can inspect them.
+type bufferedResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferedResponseWriter() *bufferedResponseWriter {
+	return &bufferedResponseWriter{
+		header: make(http.Header),
+	}
+}
+
+// Header returns the captured header map.
+func (b *bufferedResponseWriter) Header() http.Header {
+	return b.header
+}
+
+// Write appends p to the captured body.
+func (b *bufferedResponseWriter) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+// WriteHeader captures the status code, only the first call is honored.
+func (b *bufferedResponseWriter) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+// copyHeader copies the captured headers onto w.
+func (b *bufferedResponseWriter) copyHeader(w http.ResponseWriter) {
+	for k, v := range b.header {
+		w.Header()[k] = v
+	}
+}
+
+// headResponseWriter is an http.ResponseWriter capturing the headers and status
+// written by an engine while passing the body through to an io.Writer.
+type headResponseWriter struct {
+	w      io.Writer
+	header http.Header
+	status int
+}
+
+func newHeadResponseWriter(w io.Writer) *headResponseWriter {
+	return &headResponseWriter{
+		w:      w,
+		header: make(http.Header),
+	}
+}
+
+// Header returns the captured header map.
+func (h *headResponseWriter) Header() http.Header {
+	return h.header
+}
+
+// Write writes p to the underlying writer.
+func (h *headResponseWriter) Write(p []byte) (int, error) {
+	if h.status == 0 {
+		h.status = http.StatusOK
+	}
+	return h.w.Write(p)
+}
+
+// WriteHeader captures the status code, only the first call is honored.
+func (h *headResponseWriter) WriteHeader(status int) {
+	if h.status == 0 {
+		h.status = status
+	}
+}
+
+// bodylessResponseWriter discards the body while letting the headers through,
+// as required for responses to HEAD requests.
+type bodylessResponseWriter struct {
+	http.ResponseWriter
+}
+
+// Write discards p, reporting it as fully written.
+func (b bodylessResponseWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+// noContentResponseWriter drops the headers describing a body when the status
+// is written, as a 204 No Content has none.
+type noContentResponseWriter struct {
+	http.ResponseWriter
+}
+
+// WriteHeader removes the Content-Type and Content-Length headers before writing the status.
+func (n noContentResponseWriter) WriteHeader(status int) {
+	n.Header().Del(ContentType)
+	n.Header().Del(ContentLength)
+	n.ResponseWriter.WriteHeader(status)
+}
+
+// statusResponseWriter writes its status in place of the 200 given to WriteHeader.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+// WriteHeader writes the overriding status, unless the engine chose another
+// status than 200 itself, e.g. a 304 or a 204 without a body. Only the first
+// call is honored.
+func (s *statusResponseWriter) WriteHeader(status int) {
+	if s.wroteHeader {
+		return
+	}
+	s.wroteHeader = true
+	if status == 0 || status == http.StatusOK {
+		status = s.status
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// Write writes p, writing the overriding status first if needed.
+func (s *statusResponseWriter) Write(p []byte) (int, error) {
+	s.WriteHeader(http.StatusOK)
+	return s.ResponseWriter.Write(p)
+}
+
+// Flush flushes the underlying writer if it's an http.Flusher.
+func (s *statusResponseWriter) Flush() {
+	flush(s.ResponseWriter)
+}
+
+// countingWriter counts the bytes written to an io.Writer.
+type countingWriter struct {
+	io.Writer
+	n int
+}
+
+// Write writes p, counting the bytes written.
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.Writer.Write(p)
+	c.n += n
+	return n, err
+}
+
+func (c *countingWriter) count() int {
+	return c.n
+}
+
+// countingResponseWriter counts the body bytes written to an http.ResponseWriter.
+type countingResponseWriter struct {
+	http.ResponseWriter
+	n int
+}
+
+// Write writes p, counting the bytes written.
+func (c *countingResponseWriter) Write(p []byte) (int, error) {
+	n, err := c.ResponseWriter.Write(p)
+	c.n += n
+	return n, err
+}
+
+// Flush flushes the underlying writer if it's an http.Flusher.
+func (c *countingResponseWriter) Flush() {
+	flush(c.ResponseWriter)
+}
+
+func (c *countingResponseWriter) count() int {
+	return c.n
+}
+
+// newCountingWriter wraps w to count the bytes written to it, keeping it an
+// http.ResponseWriter if it is one.
+func newCountingWriter(w io.Writer) interface {
+	io.Writer
+	count() int
+} {
+	if hw, ok := w.(http.ResponseWriter); ok {
+		return &countingResponseWriter{ResponseWriter: hw}
+	}
+	return &countingWriter{Writer: w}
+}
+
+// errWriter writes to an io.Writer until the first error, which it keeps along
+// with the number of bytes written so far. Engines writing their output in
+// several parts check the error once at the end.
+type errWriter struct {
+	w   io.Writer
+	n   int
+	err error
+}
+
+// Write writes p unless it's empty or a previous write failed.
+func (ew *errWriter) Write(p []byte) {
+	if ew.err != nil || len(p) == 0 {
+		return
+	}
+	n, err := ew.w.Write(p)
+	ew.n += n
+	ew.err = err
+}
+
+// flush sends any buffered data to the client if w is an http.Flusher.
+func flush(w io.Writer) {
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
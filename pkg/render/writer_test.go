@@ -0,0 +1,27 @@
+package render
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlush(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	flush(recorder)
+	assert.True(t, recorder.Flushed)
+
+	// Writers which can't flush are left alone.
+	buf := bytes.NewBufferString("hello")
+	flush(buf)
+	assert.Equal(t, "hello", buf.String())
+}
+
+func TestStatusResponseWriter_Flush(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	flush(&statusResponseWriter{ResponseWriter: recorder, status: http.StatusCreated})
+	assert.True(t, recorder.Flushed)
+}
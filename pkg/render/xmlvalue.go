@@ -0,0 +1,28 @@
+package render
+
+import (
+	"encoding/xml"
+)
+
+// CDATA is a string the XML engine emits as a <![CDATA[...]]> section, e.g. to
+// embed HTML snippets or scripts. A "]]>" within the string is split across two
+// sections.
+type CDATA string
+
+// MarshalXML implements xml.Marshaler.
+func (c CDATA) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(struct {
+		Text string `xml:",cdata"`
+	}{string(c)}, start)
+}
+
+// RawXML is a fragment of XML the XML engine emits as is, without escaping it.
+// The fragment must be well-formed, it isn't checked.
+type RawXML []byte
+
+// MarshalXML implements xml.Marshaler.
+func (r RawXML) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(struct {
+		Inner []byte `xml:",innerxml"`
+	}{r}, start)
+}
@@ -7,9 +7,9 @@ import (
 
 	"github.com/containous/mux"
 	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/render"
 	"github.com/google/go-github/github"
 	goversion "github.com/hashicorp/go-version"
-	"github.com/unrolled/render"
 )
 
 var (